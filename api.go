@@ -0,0 +1,260 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The statuses of the jobs served by the API.
+const (
+	StatusFailed   = "failed"
+	StatusUnstable = "unstable"
+	StatusAborted  = "aborted"
+	StatusUnknown  = "unknown"
+	StatusSuccess  = "success"
+)
+
+const (
+	// DefaultPerPage is the number of jobs per page, if no page size is requested.
+	DefaultPerPage = 50
+	// MaxPerPage is the maximum number of jobs per page.
+	MaxPerPage = 500
+)
+
+// jobSortKeys are the sort keys of the jobs along with the comparison of two jobs in ascending order.
+var jobSortKeys = map[string]func(a, b *APIJob) bool{
+	"brokenSince": func(a, b *APIJob) bool {
+		return a.BrokenSince != nil && (b.BrokenSince == nil || a.BrokenSince.Before(*b.BrokenSince))
+	},
+	"name": func(a, b *APIJob) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+	"failCount": func(a, b *APIJob) bool {
+		return a.FailCount < b.FailCount
+	},
+}
+
+// APIJob is a job along with its instance, as served by the API.
+type APIJob struct {
+	Instance     string `json:"instance"`
+	InstanceType string `json:"instanceType"`
+	Job
+	// Status is one of StatusFailed, StatusUnstable, StatusAborted, StatusUnknown and StatusSuccess.
+	Status string `json:"status"`
+	// FailCount is the number of failed tests of the last build, as far as the CI system reports them.
+	FailCount int  `json:"failCount"`
+	Muted     bool `json:"muted"`
+}
+
+// JobQuery selects, sorts and paginates the jobs served by the API.
+type JobQuery struct {
+	// Instance is a pattern which matches the name of the instance, in the syntax of path.Match except that '*' also
+	// matches '/'.
+	Instance string
+	Status   string
+	// Owner is the name of the team owning the jobs.
+	Owner string
+	// Text is searched case-insensitively in the ID, name and instance of the jobs.
+	Text string
+	// Sort is one of the keys 'brokenSince', 'name' and 'failCount', sorting in descending order if prefixed by '-'.
+	Sort string
+	// Muted includes the muted jobs.
+	Muted   bool
+	Page    int
+	PerPage int
+}
+
+// JobPage is a page of the jobs selected by a JobQuery.
+type JobPage struct {
+	Jobs []*APIJob `json:"jobs"`
+	// Total is the number of selected jobs on all pages.
+	Total   int `json:"total"`
+	Page    int `json:"page"`
+	PerPage int `json:"perPage"`
+}
+
+// ParseJobQuery parses the query parameters 'instance', 'status', 'owner', 'q', 'sort', 'muted', 'page' and
+// 'perPage'.
+func ParseJobQuery(values url.Values) (JobQuery, error) {
+	query := JobQuery{
+		Instance: values.Get("instance"),
+		Status:   values.Get("status"),
+		Owner:    values.Get("owner"),
+		Text:     values.Get("q"),
+		Sort:     values.Get("sort"),
+		Page:     1,
+		PerPage:  DefaultPerPage,
+	}
+
+	if _, err := path.Match(query.Instance, ""); err != nil {
+		return query, fmt.Errorf("Invalid instance pattern '%s': %s", query.Instance, err)
+	}
+	switch query.Status {
+	case "", StatusFailed, StatusUnstable, StatusAborted, StatusUnknown, StatusSuccess:
+	default:
+		return query, fmt.Errorf("Invalid status '%s'", query.Status)
+	}
+	if _, ok := jobSortKeys[strings.TrimPrefix(query.Sort, "-")]; query.Sort != "" && !ok {
+		return query, fmt.Errorf("Invalid sort key '%s'", query.Sort)
+	}
+
+	var err error
+	if muted := values.Get("muted"); muted != "" {
+		if query.Muted, err = strconv.ParseBool(muted); err != nil {
+			return query, fmt.Errorf("Invalid muted flag '%s'", muted)
+		}
+	}
+	if page := values.Get("page"); page != "" {
+		if query.Page, err = strconv.Atoi(page); err != nil || query.Page < 1 {
+			return query, fmt.Errorf("Invalid page '%s'", page)
+		}
+	}
+	if perPage := values.Get("perPage"); perPage != "" {
+		if query.PerPage, err = strconv.Atoi(perPage); err != nil || query.PerPage < 1 || query.PerPage > MaxPerPage {
+			return query, fmt.Errorf("Invalid page size '%s', it must be between 1 and %d", perPage, MaxPerPage)
+		}
+	}
+	return query, nil
+}
+
+// Values encodes the query into the query parameters parsed by ParseJobQuery, leaving out the defaults.
+func (q JobQuery) Values() url.Values {
+	values := url.Values{}
+	for name, value := range map[string]string{"instance": q.Instance, "status": q.Status, "owner": q.Owner, "q": q.Text, "sort": q.Sort} {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	if q.Muted {
+		values.Set("muted", "true")
+	}
+	if q.Page > 1 {
+		values.Set("page", strconv.Itoa(q.Page))
+	}
+	if q.PerPage > 0 && q.PerPage != DefaultPerPage {
+		values.Set("perPage", strconv.Itoa(q.PerPage))
+	}
+	return values
+}
+
+// matches returns true, if the job is selected by the query.
+func (q JobQuery) matches(job *APIJob) bool {
+	if q.Instance != "" {
+		if !MatchPattern(q.Instance, job.Instance) {
+			return false
+		}
+	}
+	if q.Status != "" && job.Status != q.Status {
+		return false
+	}
+	if job.Muted && !q.Muted {
+		return false
+	}
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		return strings.Contains(strings.ToLower(job.ID), text) || strings.Contains(strings.ToLower(job.Name), text) ||
+			strings.Contains(strings.ToLower(job.Instance), text)
+	}
+	return true
+}
+
+// Jobs retrieves the jobs of all instances selected by the query, in the order of the configuration of the
+// instances unless sorted otherwise, and returns the requested page.
+func (d *Dashboard) Jobs(query JobQuery) *JobPage {
+	jobs := SelectJobs(d.FetchFiltered(Filter{Team: query.Owner}), query)
+
+	page := &JobPage{Jobs: make([]*APIJob, 0), Total: len(jobs), Page: query.Page, PerPage: query.PerPage}
+	if page.Page < 1 {
+		page.Page = 1
+	}
+	if page.PerPage < 1 {
+		page.PerPage = DefaultPerPage
+	}
+	if start := (page.Page - 1) * page.PerPage; start < len(jobs) {
+		end := start + page.PerPage
+		if end > len(jobs) {
+			end = len(jobs)
+		}
+		page.Jobs = jobs[start:end]
+	}
+	return page
+}
+
+// SelectJobs returns all jobs of the given aggregations which are selected by the query, sorted as requested but
+// not paginated. The owner of the query isn't applied, as the aggregations are expected to be filtered by team.
+func SelectJobs(aggregations []*InstanceAggregation, query JobQuery) []*APIJob {
+	var jobs []*APIJob
+	for _, aggregation := range aggregations {
+		for _, list := range []struct {
+			jobs  []Job
+			muted bool
+		}{{aggregation.Jobs, false}, {aggregation.Muted, true}} {
+			for _, job := range list.jobs {
+				apiJob := &APIJob{
+					Instance:     aggregation.Name,
+					InstanceType: aggregation.Type,
+					Job:          job,
+					Status:       statusOf(job),
+					FailCount:    failCountOf(job),
+					Muted:        list.muted,
+				}
+				if query.matches(apiJob) {
+					jobs = append(jobs, apiJob)
+				}
+			}
+		}
+	}
+
+	if less, ok := jobSortKeys[strings.TrimPrefix(query.Sort, "-")]; ok {
+		descending := strings.HasPrefix(query.Sort, "-")
+		sort.SliceStable(jobs, func(i, j int) bool {
+			if descending {
+				return less(jobs[j], jobs[i])
+			}
+			return less(jobs[i], jobs[j])
+		})
+	}
+	return jobs
+}
+
+// Events returns the transitions of jobs and instances after the given time, which are kept for a week.
+func (d *Dashboard) Events(since time.Time) []Event {
+	events := d.eventsSince(since)
+	if events == nil {
+		events = make([]Event, 0)
+	}
+	return events
+}
+
+// statusOf maps the state of a job onto its status.
+func statusOf(job Job) string {
+	switch {
+	case job.Error != "":
+		return StatusUnknown
+	case !job.Broken:
+		return StatusSuccess
+	case strings.HasPrefix(job.Color, "yellow"), strings.HasPrefix(job.Color, "amber"):
+		return StatusUnstable
+	case strings.HasPrefix(job.Color, "aborted"):
+		return StatusAborted
+	case strings.HasPrefix(job.Color, "grey"), strings.HasPrefix(job.Color, "notbuilt"), strings.HasPrefix(job.Color, "disabled"):
+		return StatusUnknown
+	}
+	return StatusFailed
+}
+
+// failCountOf returns the number of failed tests of the last build of a job, which is only reported by Jenkins.
+func failCountOf(job Job) int {
+	count := 0
+	if jenkinsJob, ok := job.Details.(JenkinsJob); ok {
+		for _, action := range jenkinsJob.LastBuild.Actions {
+			count += action.FailCount
+		}
+	}
+	return count
+}
@@ -0,0 +1,185 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard_Jobs(t *testing.T) {
+	tested := JenkinsJob{}
+	tested.LastBuild.Actions = []JenkinsAction{{FailCount: 4}}
+	release := &TestProvider{name: "Release", jobs: []Job{
+		{ID: "camunda-bpm-platform", Name: "Camunda BPM Platform", Color: "yellow", Broken: true, Details: tested},
+		{ID: "docs", Name: "docs", Color: "red", Broken: true},
+		{ID: "website", Name: "website", Color: "blue"},
+		{ID: "flaky-it", Name: "flaky-it", Color: "red", Broken: true},
+	}}
+	ci := &TestProvider{name: "CI", jobs: []Job{{ID: "zeebe", Name: "Zeebe", Color: "aborted", Broken: true}}}
+	d := New(release, ci)
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "Release", Job: "flaky-*"}})
+	d.UseMutes(mutes)
+	ownership, _ := NewOwnership([]*Team{{Name: "platform"}}, []*OwnershipRule{{Team: "platform", Job: "camunda-*"}})
+	d.UseOwnership(ownership)
+
+	tests := []struct {
+		query string
+		ids   string
+		total int
+	}{
+		{"", "camunda-bpm-platform,docs,website,zeebe", 4},
+		{"muted=true", "camunda-bpm-platform,docs,website,flaky-it,zeebe", 5},
+		{"instance=Rel*", "camunda-bpm-platform,docs,website", 3},
+		{"status=failed", "docs", 1},
+		{"status=unstable", "camunda-bpm-platform", 1},
+		{"status=aborted", "zeebe", 1},
+		{"status=success", "website", 1},
+		{"owner=platform", "camunda-bpm-platform", 1},
+		{"q=ZEE", "zeebe", 1},
+		{"q=rel", "camunda-bpm-platform,docs,website", 3},
+		{"sort=name", "camunda-bpm-platform,docs,website,zeebe", 4},
+		{"sort=-name", "zeebe,website,docs,camunda-bpm-platform", 4},
+		{"sort=-failCount", "camunda-bpm-platform,docs,website,zeebe", 4},
+		{"sort=name&perPage=3&page=2", "zeebe", 4},
+		{"perPage=2", "camunda-bpm-platform,docs", 4},
+		{"page=3&perPage=2", "", 4},
+	}
+	for _, test := range tests {
+		values, _ := url.ParseQuery(test.query)
+		query, err := ParseJobQuery(values)
+		if err != nil {
+			t.Errorf("Query '%s' should be valid, got %s", test.query, err)
+			continue
+		}
+
+		page := d.Jobs(query)
+		ids := make([]string, len(page.Jobs))
+		for i, job := range page.Jobs {
+			ids[i] = job.ID
+		}
+		if strings.Join(ids, ",") != test.ids || page.Total != test.total {
+			t.Errorf("Wrong jobs of query '%s'. Expected: %s (%d), got: %s (%d)", test.query, test.ids, test.total, strings.Join(ids, ","), page.Total)
+		}
+	}
+
+	job := d.Jobs(JobQuery{Status: StatusUnstable}).Jobs[0]
+	if job.Instance != "Release" || job.InstanceType != "test" || job.FailCount != 4 || job.Owner == nil || job.Muted {
+		t.Fatalf("Job should be described along with its instance, got %+v", job)
+	}
+}
+
+func TestDashboard_Jobs_SortBrokenSince(t *testing.T) {
+	provider := &TestProvider{name: "Release", jobs: []Job{{ID: "docs", Color: "red", Broken: true}}}
+	d := New(provider)
+	d.Fetch()
+	time.Sleep(10 * time.Millisecond)
+	provider.jobs = []Job{{ID: "website", Color: "red", Broken: true}, {ID: "docs", Color: "red", Broken: true}, {ID: "deploy", Color: "blue"}}
+	d.Fetch()
+
+	jobs := d.Jobs(JobQuery{Sort: "brokenSince"}).Jobs
+	if len(jobs) != 3 || jobs[0].ID != "docs" || jobs[1].ID != "website" || jobs[2].ID != "deploy" {
+		t.Fatalf("Jobs should be sorted by the time they broke, got %+v", jobs)
+	}
+}
+
+func TestSelectJobs(t *testing.T) {
+	aggregations := []*InstanceAggregation{
+		{Aggregation: Aggregation{Name: "Release"}, Jobs: []Job{{ID: "docs", Name: "docs", Color: "red", Broken: true}, {ID: "website", Name: "website", Color: "blue"}}},
+		{Aggregation: Aggregation{Name: "CI"}, Jobs: []Job{{ID: "zeebe", Name: "zeebe", Color: "red", Broken: true}}, Muted: []Job{{ID: "flaky", Color: "red", Broken: true}}},
+	}
+
+	jobs := SelectJobs(aggregations, JobQuery{Status: StatusFailed, Sort: "-name", PerPage: 1})
+	if len(jobs) != 2 || jobs[0].ID != "zeebe" || jobs[1].ID != "docs" {
+		t.Fatalf("All selected jobs should be returned sorted, got %+v", jobs)
+	}
+}
+
+func TestSelectJobs_InstancePatternMatchesSlashes(t *testing.T) {
+	aggregations := []*InstanceAggregation{
+		{Aggregation: Aggregation{Name: "camunda/zeebe"}, Jobs: []Job{{ID: "CI", Name: "CI", Color: "red", Broken: true}}},
+		{Aggregation: Aggregation{Name: "Release"}, Jobs: []Job{{ID: "docs", Name: "docs", Color: "red", Broken: true}}},
+	}
+
+	jobs := SelectJobs(aggregations, JobQuery{Instance: "camunda*"})
+	if len(jobs) != 1 || jobs[0].Instance != "camunda/zeebe" {
+		t.Fatalf("Instance pattern should match like the ones of mutes, got %+v", jobs)
+	}
+}
+
+func TestParseJobQuery(t *testing.T) {
+	query, err := ParseJobQuery(url.Values{})
+	if err != nil || query.Page != 1 || query.PerPage != DefaultPerPage {
+		t.Fatalf("Query should default to the first page, got %+v %v", query, err)
+	}
+
+	for _, invalid := range []string{"instance=[Release", "status=red", "sort=color", "sort=+name", "muted=maybe", "page=0",
+		"page=first", "perPage=0", "perPage=501"} {
+		values, _ := url.ParseQuery(invalid)
+		if _, err := ParseJobQuery(values); err == nil {
+			t.Errorf("Query '%s' should be invalid", invalid)
+		}
+	}
+}
+
+func TestJobQuery_Values(t *testing.T) {
+	query := JobQuery{Instance: "Rel*", Status: StatusFailed, Owner: "platform", Text: "docs", Sort: "-name", Muted: true, Page: 2, PerPage: 10}
+
+	parsed, err := ParseJobQuery(query.Values())
+	if err != nil || parsed != query {
+		t.Fatalf("Query should be parsed from its values. Expected: %+v, got: %+v %v", query, parsed, err)
+	}
+	if values := (JobQuery{Page: 1, PerPage: DefaultPerPage}).Values(); len(values) != 0 {
+		t.Fatalf("Defaults should be left out, got %v", values)
+	}
+}
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		job    Job
+		status string
+	}{
+		{Job{Color: "red", Broken: true}, StatusFailed},
+		{Job{Color: "red_anime", Broken: true}, StatusFailed},
+		{Job{Color: "yellow", Broken: true}, StatusUnstable},
+		{Job{Color: "amber", Broken: true}, StatusUnstable},
+		{Job{Color: "aborted", Broken: true}, StatusAborted},
+		{Job{Color: "notbuilt", Broken: true}, StatusUnknown},
+		{Job{Color: "grey", Broken: true, Error: "not found"}, StatusUnknown},
+		{Job{Color: "blue"}, StatusSuccess},
+	}
+	for i, test := range tests {
+		if status := statusOf(test.job); status != test.status {
+			t.Errorf("Wrong status of job #%d. Expected: %s, got: %s", i, test.status, status)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	content, err := ioutil.ReadFile("assets/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document struct {
+		Paths      map[string]interface{}
+		Components map[string]map[string]interface{}
+	}
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("OpenAPI document should be valid JSON: %s", err)
+	}
+	for _, path := range []string{"/summary", "/instances", "/jobs", "/events", "/claims", "/claims/{instance}/{job}", "/mutes", "/mutes/{id}"} {
+		if _, ok := document.Paths[path]; !ok {
+			t.Errorf("OpenAPI document should describe '%s'", path)
+		}
+	}
+
+	for _, ref := range regexp.MustCompile(`"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(content), -1) {
+		if _, ok := document.Components[ref[1]][ref[2]]; !ok {
+			t.Errorf("OpenAPI document references the missing component %s", ref[0])
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Board is a named selection of instances and jobs, which is shown on its own page.
+type Board struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	// Instances and Jobs are patterns in the syntax of path.Match, whose '*' also matches '/'. They select all
+	// instances respectively jobs if empty.
+	Instances []string `json:"instances,omitempty"`
+	Jobs      []string `json:"jobs,omitempty"`
+	// Views are the types of the CI systems shown on the board, e.g. 'jenkins' or 'travis', all types if empty.
+	Views []string `json:"views,omitempty"`
+	// Teams select the jobs owned by one of the teams, all jobs if empty.
+	Teams []string `json:"teams,omitempty"`
+}
+
+// BoardAggregation is a board along with the broken jobs of its instances.
+type BoardAggregation struct {
+	Name      string                 `json:"name"`
+	Title     string                 `json:"title"`
+	Instances []*InstanceAggregation `json:"instances"`
+}
+
+// Validate checks that the board has a name and its patterns are valid.
+func (b *Board) Validate() error {
+	if b.Name == "" {
+		return errors.New("A board requires a name.")
+	}
+	for _, pattern := range append(append([]string{}, b.Instances...), b.Jobs...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Board '%s' has an invalid pattern '%s': %s", b.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// selects returns true, if the instance is shown on the board.
+func (b *Board) selects(instance Aggregation) bool {
+	if len(b.Views) > 0 && !containsString(b.Views, instance.Type) {
+		return false
+	}
+	return len(b.Instances) == 0 || matchesAny(b.Instances, instance.Name)
+}
+
+// apply removes the jobs and muted jobs of the aggregation which aren't shown on the board.
+func (b *Board) apply(aggregation *InstanceAggregation) {
+	if len(b.Jobs) == 0 && len(b.Teams) == 0 {
+		return
+	}
+
+	selected := func(jobs []Job) []Job {
+		filtered := make([]Job, 0, len(jobs))
+		for _, job := range jobs {
+			if len(b.Jobs) > 0 && !matchesAny(b.Jobs, job.ID) {
+				continue
+			}
+			if len(b.Teams) > 0 && (job.Owner == nil || !containsString(b.Teams, job.Owner.Name)) {
+				continue
+			}
+			filtered = append(filtered, job)
+		}
+		return filtered
+	}
+	aggregation.Jobs = selected(aggregation.Jobs)
+	aggregation.Muted = selected(aggregation.Muted)
+}
+
+// UseBoards makes the given boards available to FetchBoard.
+func (d *Dashboard) UseBoards(boards ...*Board) {
+	d.boards = append(d.boards, boards...)
+}
+
+// Boards returns the configured boards.
+func (d *Dashboard) Boards() []*Board {
+	return d.boards
+}
+
+// FetchBoard retrieves the broken jobs shown on the board with the given name, which are further narrowed down by
+// the filter. It returns nil, if there is no such board.
+func (d *Dashboard) FetchBoard(name string, filter Filter) *BoardAggregation {
+	var board *Board
+	for _, b := range d.boards {
+		if b.Name == name {
+			board = b
+			break
+		}
+	}
+	if board == nil {
+		return nil
+	}
+
+	var providers []Provider
+	for _, provider := range d.providers {
+		if board.selects(provider.Info()) {
+			providers = append(providers, provider)
+		}
+	}
+
+	title := board.Title
+	if title == "" {
+		title = board.Name
+	}
+	aggregation := &BoardAggregation{Name: board.Name, Title: title, Instances: d.fetch(providers, filter)}
+	for _, instance := range aggregation.Instances {
+		board.apply(instance)
+	}
+	return aggregation
+}
@@ -0,0 +1,60 @@
+package dashboard
+
+import (
+	"testing"
+)
+
+func TestDashboard_FetchBoard(t *testing.T) {
+	release := &TestProvider{name: "Release", jobs: []Job{
+		{ID: "camunda-optimize-docs", Color: "red", Broken: true},
+		{ID: "camunda-optimize-it", Color: "red", Broken: true},
+		{ID: "camunda-bpm-platform", Color: "red", Broken: true},
+		{ID: "camunda-optimize-folder/master", Color: "red", Broken: true},
+	}}
+	ci := &TestProvider{name: "CI", jobs: []Job{{ID: "camunda-optimize-ci", Color: "red", Broken: true}}}
+	d := New(release, ci)
+	ownership, _ := NewOwnership([]*Team{{Name: "optimize"}, {Name: "docs"}}, []*OwnershipRule{
+		{Team: "docs", Job: "*-docs"},
+		{Team: "optimize", Job: "camunda-optimize-*"},
+	})
+	d.UseOwnership(ownership)
+	d.UseBoards(
+		&Board{Name: "optimize", Title: "Optimize", Instances: []string{"Rel*"}, Views: []string{"test"}, Jobs: []string{"camunda-optimize-*"}},
+		&Board{Name: "docs", Teams: []string{"docs"}},
+		&Board{Name: "travis", Views: []string{"travis"}},
+	)
+
+	board := d.FetchBoard("optimize", Filter{})
+	if board.Title != "Optimize" || len(board.Instances) != 1 || board.Instances[0].Name != "Release" {
+		t.Fatalf("Board should show the selected instances, got %+v", board)
+	}
+	if jobs := board.Instances[0].Jobs; len(jobs) != 3 || jobs[0].ID != "camunda-optimize-docs" || jobs[1].ID != "camunda-optimize-it" || jobs[2].ID != "camunda-optimize-folder/master" {
+		t.Fatalf("Board should show the selected jobs, got %+v", jobs)
+	}
+	if jobs := d.FetchBoard("optimize", Filter{Team: "optimize"}).Instances[0].Jobs; len(jobs) != 2 || jobs[0].ID != "camunda-optimize-it" {
+		t.Fatalf("Board should be filtered by the team, got %+v", jobs)
+	}
+
+	board = d.FetchBoard("docs", Filter{})
+	if board.Title != "docs" || len(board.Instances) != 2 || len(board.Instances[0].Jobs) != 1 || len(board.Instances[1].Jobs) != 0 {
+		t.Fatalf("Board should show the jobs of its teams, got %+v", board)
+	}
+	if board := d.FetchBoard("travis", Filter{}); len(board.Instances) != 0 {
+		t.Fatalf("Board should only show instances of its views, got %+v", board.Instances)
+	}
+	if board := d.FetchBoard("missing", Filter{}); board != nil {
+		t.Fatalf("Missing board shouldn't be fetched, got %+v", board)
+	}
+}
+
+func TestBoard_Validate(t *testing.T) {
+	if err := (&Board{Name: "release", Instances: []string{"Release"}, Jobs: []string{"camunda-*"}}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, board := range []*Board{{}, {Name: "release", Instances: []string{"[Release"}}, {Name: "release", Jobs: []string{"[camunda"}}} {
+		if err := board.Validate(); err == nil {
+			t.Errorf("Board #%d should be invalid", i)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	claimSourceDashboard = "dashboard"
+	claimSourceJenkins   = "jenkins"
+)
+
+// Claim marks a broken job as being taken care of by someone.
+type Claim struct {
+	Instance  string    `json:"instance"`
+	Job       string    `json:"job"`
+	User      string    `json:"user"`
+	Note      string    `json:"note"`
+	Timestamp time.Time `json:"timestamp"`
+	// Source is 'dashboard' for claims made through the dashboard, or 'jenkins' for claims of the Jenkins Claim plugin.
+	Source string `json:"source"`
+}
+
+// ClaimStore holds all claims made through the dashboard and persists them to a JSON file.
+// An empty path keeps the claims in memory only.
+type ClaimStore struct {
+	path     string
+	readOnly bool
+	mutex    sync.RWMutex
+	claims   map[string]*Claim
+}
+
+// NewClaimStore returns a ClaimStore backed by the file at the given path.
+// Previously persisted claims are loaded, a missing file is not an error.
+func NewClaimStore(path string) (*ClaimStore, error) {
+	store := &ClaimStore{path: path, claims: make(map[string]*Claim)}
+	if path == "" {
+		return store, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read claims file: %s", err)
+	}
+
+	var claims []*Claim
+	if err := json.Unmarshal(content, &claims); err != nil {
+		return nil, fmt.Errorf("Error while unmarshalling claims file '%s': %s", path, err)
+	}
+	for _, claim := range claims {
+		store.claims[claimKey(claim.Instance, claim.Job)] = claim
+	}
+
+	return store, nil
+}
+
+// NewReadOnlyClaimStore returns a ClaimStore with the claims persisted to the file at the given path, which is never
+// written. Claims can't be made or released, and claims of fixed jobs are kept.
+func NewReadOnlyClaimStore(path string) (*ClaimStore, error) {
+	store, err := NewClaimStore(path)
+	if err != nil {
+		return nil, err
+	}
+	store.readOnly = true
+	return store, nil
+}
+
+func claimKey(instance string, job string) string {
+	return instance + "/" + job
+}
+
+// Claim claims the job of the given instance for the user, replacing any existing claim.
+func (s *ClaimStore) Claim(instance string, job string, user string, note string) (*Claim, error) {
+	if user == "" {
+		return nil, errors.New("A claim requires a user.")
+	}
+
+	claim := &Claim{
+		Instance:  instance,
+		Job:       job,
+		User:      user,
+		Note:      note,
+		Timestamp: time.Now().UTC(),
+		Source:    claimSourceDashboard,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := claimKey(instance, job)
+	previous, claimed := s.claims[key]
+	s.claims[key] = claim
+	if err := s.save(); err != nil {
+		// the claims in memory have to match the persisted ones
+		if claimed {
+			s.claims[key] = previous
+		} else {
+			delete(s.claims, key)
+		}
+		return nil, err
+	}
+	return claim, nil
+}
+
+// Release removes the claim of the job of the given instance.
+// It returns false, if the job wasn't claimed.
+func (s *ClaimStore) Release(instance string, job string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := claimKey(instance, job)
+	claim, ok := s.claims[key]
+	if !ok {
+		return false, nil
+	}
+	delete(s.claims, key)
+	if err := s.save(); err != nil {
+		s.claims[key] = claim
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the claim of the job of the given instance or nil, if the job isn't claimed.
+func (s *ClaimStore) Get(instance string, job string) *Claim {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.claims[claimKey(instance, job)]
+}
+
+// All returns all claims ordered by their timestamp.
+func (s *ClaimStore) All() []*Claim {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	claims := make([]*Claim, 0, len(s.claims))
+	for _, claim := range s.claims {
+		claims = append(claims, claim)
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Timestamp.Before(claims[j].Timestamp)
+	})
+	return claims
+}
+
+// ReleaseFixed releases all claims of the instance whose job is not part of the given broken jobs anymore.
+func (s *ClaimStore) ReleaseFixed(instance string, brokenJobs []string) error {
+	if s.readOnly {
+		return nil
+	}
+
+	broken := make(map[string]bool, len(brokenJobs))
+	for _, job := range brokenJobs {
+		broken[job] = true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	released := make(map[string]*Claim)
+	for key, claim := range s.claims {
+		if claim.Instance == instance && !broken[claim.Job] {
+			delete(s.claims, key)
+			released[key] = claim
+		}
+	}
+
+	if len(released) == 0 {
+		return nil
+	}
+	if err := s.save(); err != nil {
+		for key, claim := range released {
+			s.claims[key] = claim
+		}
+		return err
+	}
+	return nil
+}
+
+// save writes all claims to the file of the store. The caller must hold the lock.
+func (s *ClaimStore) save() error {
+	if s.readOnly {
+		return errors.New("Claims are read-only.")
+	}
+	if s.path == "" {
+		return nil
+	}
+
+	claims := make([]*Claim, 0, len(s.claims))
+	for _, claim := range s.claims {
+		claims = append(claims, claim)
+	}
+	if err := writeJSONFile(s.path, claims); err != nil {
+		return fmt.Errorf("Unable to persist claims: %s", err)
+	}
+	return nil
+}
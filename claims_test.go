@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClaimStore_ClaimAndRelease(t *testing.T) {
+	store, _ := NewClaimStore("")
+
+	claim, err := store.Claim("Release", "camunda-bpm-platform", "jane", "flaky test")
+	assertNoError(err, t, "claim")
+	if claim.User != "jane" || claim.Note != "flaky test" || claim.Source != claimSourceDashboard {
+		t.Fatalf("Wrong claim returned: %+v", claim)
+	}
+	if claim.Timestamp.IsZero() {
+		t.Fatal("Claim timestamp should be set.")
+	}
+
+	if store.Get("Release", "camunda-bpm-platform") != claim {
+		t.Fatal("Claim should be returned for the claimed job.")
+	}
+	if store.Get("CI", "camunda-bpm-platform") != nil {
+		t.Fatal("Claims must be scoped by instance.")
+	}
+
+	released, err := store.Release("Release", "camunda-bpm-platform")
+	assertNoError(err, t, "release")
+	if !released {
+		t.Fatal("Claimed job should have been released.")
+	}
+
+	released, _ = store.Release("Release", "camunda-bpm-platform")
+	if released {
+		t.Fatal("Releasing an unclaimed job should return false.")
+	}
+}
+
+func TestClaimStore_ClaimRequiresUser(t *testing.T) {
+	store, _ := NewClaimStore("")
+
+	if _, err := store.Claim("Release", "camunda-bpm-platform", "", ""); err == nil {
+		t.Fatal("Expecting an error for a claim without user, got nil")
+	}
+}
+
+func TestClaimStore_PersistsClaims(t *testing.T) {
+	dir, err := ioutil.TempDir("", "claims")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "claims.json")
+
+	store, err := NewClaimStore(path)
+	assertNoError(err, t, "claim store")
+	store.Claim("Release", "camunda-bpm-platform", "jane", "flaky test")
+	store.Claim("Release", "docs/master", "john", "")
+	store.Release("Release", "docs/master")
+
+	reloaded, err := NewClaimStore(path)
+	assertNoError(err, t, "reloaded claim store")
+
+	claims := reloaded.All()
+	if len(claims) != 1 {
+		t.Fatalf("Wrong number of claims loaded. Expected 1, got %d", len(claims))
+	}
+	if claims[0].Job != "camunda-bpm-platform" || claims[0].User != "jane" {
+		t.Fatalf("Wrong claim loaded: %+v", claims[0])
+	}
+}
+
+func TestClaimStore_ReleaseFixed(t *testing.T) {
+	store, _ := NewClaimStore("")
+	store.Claim("Release", "still-broken", "jane", "")
+	store.Claim("Release", "fixed", "jane", "")
+	store.Claim("CI", "fixed", "john", "")
+
+	assertNoError(store.ReleaseFixed("Release", []string{"still-broken"}), t, "release fixed")
+
+	if store.Get("Release", "still-broken") == nil {
+		t.Error("Claim of a broken job must not be released.")
+	}
+	if store.Get("Release", "fixed") != nil {
+		t.Error("Claim of a fixed job should have been released.")
+	}
+	if store.Get("CI", "fixed") == nil {
+		t.Error("Claims of other instances must not be released.")
+	}
+}
+
+func TestClaimStore_KeepsClaimsOnSaveError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "claims")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewClaimStore(filepath.Join(dir, "claims.json"))
+	assertNoError(err, t, "claim store")
+	store.Claim("Release", "docs", "jane", "")
+	os.RemoveAll(dir)
+
+	if _, err := store.Claim("Release", "website", "jane", ""); err == nil || store.Get("Release", "website") != nil {
+		t.Errorf("Claim which couldn't be persisted must not be kept, got %v", err)
+	}
+	if released, err := store.Release("Release", "docs"); err == nil || released || store.Get("Release", "docs") == nil {
+		t.Errorf("Claim whose release couldn't be persisted must be kept, got %v", err)
+	}
+	if err := store.ReleaseFixed("Release", nil); err == nil || store.Get("Release", "docs") == nil {
+		t.Errorf("Fixed claims whose release couldn't be persisted must be kept, got %v", err)
+	}
+}
+
+func TestClaimStore_ReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "claims")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "claims.json")
+
+	store, err := NewClaimStore(path)
+	assertNoError(err, t, "claim store")
+	store.Claim("Release", "docs", "jane", "")
+	content, _ := ioutil.ReadFile(path)
+
+	readOnly, err := NewReadOnlyClaimStore(path)
+	assertNoError(err, t, "read-only claim store")
+	if err := readOnly.ReleaseFixed("Release", nil); err != nil || readOnly.Get("Release", "docs") == nil {
+		t.Errorf("Read-only store should keep the claims of fixed jobs, got %v", err)
+	}
+	if _, err := readOnly.Claim("Release", "website", "jane", ""); err == nil || readOnly.Get("Release", "website") != nil {
+		t.Errorf("Read-only store should reject claims, got %v", err)
+	}
+	if released, err := readOnly.Release("Release", "docs"); err == nil || released {
+		t.Errorf("Read-only store should reject releases, got %v", err)
+	}
+
+	if unchanged, _ := ioutil.ReadFile(path); string(unchanged) != string(content) {
+		t.Errorf("Read-only store shouldn't write the claims file, got %s", unchanged)
+	}
+}
@@ -0,0 +1,154 @@
+// Package client accesses the versioned API of a running dashboard, which is described by its OpenAPI document at
+// /api/v1/openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	httpclient "github.com/camunda-ci/camunda-ci-dashboard/http"
+)
+
+const apiPath = "/api/v1"
+
+// Client provides typed access to the API of a dashboard. Failed requests return the errors of the http package,
+// e.g. a NotFoundError for claims which don't exist.
+type Client struct {
+	http *httpclient.HTTPClient
+}
+
+// New returns a Client for the dashboard at the given base URL, e.g. 'http://localhost:8000'.
+func New(baseURL string) *Client {
+	return NewWithHTTPClient(httpclient.NewDefaultHTTPClient(baseURL))
+}
+
+// NewWithHTTPClient returns a Client which sends its requests with the given HTTPClient, e.g. to authenticate
+// with a proxy in front of the dashboard.
+func NewWithHTTPClient(httpClient *httpclient.HTTPClient) *Client {
+	return &Client{http: httpClient}
+}
+
+// Summary returns the totals of all instances, restricted to the jobs of the given team if it isn't empty.
+func (c *Client) Summary(ctx context.Context, team string) (*dashboard.Summary, error) {
+	var summary dashboard.Summary
+	if err := c.get(ctx, "/summary"+teamQuery(team), &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Instances returns the instances along with their broken and muted jobs, restricted to the jobs of the given team
+// if it isn't empty.
+func (c *Client) Instances(ctx context.Context, team string) ([]*dashboard.InstanceAggregation, error) {
+	var instances []*dashboard.InstanceAggregation
+	if err := c.get(ctx, "/instances"+teamQuery(team), &instances); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
+
+// Jobs returns the page of the jobs selected by the query.
+func (c *Client) Jobs(ctx context.Context, query dashboard.JobQuery) (*dashboard.JobPage, error) {
+	path := "/jobs"
+	if values := query.Values(); len(values) > 0 {
+		path += "?" + values.Encode()
+	}
+
+	var page dashboard.JobPage
+	if err := c.get(ctx, path, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Events returns the transitions of jobs and instances after the given time. The zero time returns the events of
+// the last 24 hours.
+func (c *Client) Events(ctx context.Context, since time.Time) ([]dashboard.Event, error) {
+	path := "/events"
+	if !since.IsZero() {
+		path += "?since=" + url.QueryEscape(since.Format(time.RFC3339))
+	}
+
+	var events []dashboard.Event
+	if err := c.get(ctx, path, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// Claims returns the claims made through the dashboard.
+func (c *Client) Claims(ctx context.Context) ([]*dashboard.Claim, error) {
+	var claims []*dashboard.Claim
+	if err := c.get(ctx, "/claims", &claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Claim claims the job of the given instance for the user.
+func (c *Client) Claim(ctx context.Context, instance string, job string, user string, note string) (*dashboard.Claim, error) {
+	body, err := json.Marshal(struct {
+		User string `json:"user"`
+		Note string `json:"note,omitempty"`
+	}{user, note})
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.http.PostToWithContext(ctx, apiPath+claimPath(instance, job), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var claim dashboard.Claim
+	if err := json.NewDecoder(response.Body).Decode(&claim); err != nil {
+		return nil, fmt.Errorf("Unable to decode claim: %s", err)
+	}
+	return &claim, nil
+}
+
+// Release releases the claim of the job of the given instance.
+func (c *Client) Release(ctx context.Context, instance string, job string) error {
+	response, err := c.http.DeleteFromWithContext(ctx, apiPath+claimPath(instance, job))
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// get requests the given path of the API and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, v interface{}) error {
+	response, err := c.http.GetFromWithContext(ctx, apiPath+path)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err := json.NewDecoder(response.Body).Decode(v); err != nil {
+		return fmt.Errorf("Unable to decode response of '%s': %s", path, err)
+	}
+	return nil
+}
+
+func teamQuery(team string) string {
+	if team == "" {
+		return ""
+	}
+	return "?team=" + url.QueryEscape(team)
+}
+
+// claimPath returns the path of the claim of a job, whose ID may contain slashes, e.g. of jobs inside Jenkins folders.
+func claimPath(instance string, job string) string {
+	segments := strings.Split(job, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/claims/" + url.PathEscape(instance) + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	httpclient "github.com/camunda-ci/camunda-ci-dashboard/http"
+)
+
+// apiServer serves the given responses by request URI and records the requests.
+func apiServer(t *testing.T, responses map[string]interface{}, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+
+		response, ok := responses[r.Method+" "+r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestClient_Summary(t *testing.T) {
+	var requests []string
+	server := apiServer(t, map[string]interface{}{
+		"GET /api/v1/summary?team=platform+team": dashboard.Summary{Broken: 3, Instances: 2, InstancesDown: 1},
+	}, &requests)
+	defer server.Close()
+
+	summary, err := New(server.URL).Summary(context.Background(), "platform team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Broken != 3 || summary.Instances != 2 || summary.InstancesDown != 1 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+}
+
+func TestClient_Jobs(t *testing.T) {
+	var requests []string
+	server := apiServer(t, map[string]interface{}{
+		"GET /api/v1/jobs?page=2&sort=-brokenSince&status=failed": dashboard.JobPage{
+			Jobs:  []*dashboard.APIJob{{Instance: "Release", Job: dashboard.Job{ID: "camunda-docs"}, Status: dashboard.StatusFailed}},
+			Total: 11, Page: 2, PerPage: dashboard.DefaultPerPage,
+		},
+	}, &requests)
+	defer server.Close()
+
+	page, err := New(server.URL).Jobs(context.Background(), dashboard.JobQuery{Status: dashboard.StatusFailed, Sort: "-brokenSince", Page: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 11 || len(page.Jobs) != 1 || page.Jobs[0].ID != "camunda-docs" || page.Jobs[0].Instance != "Release" {
+		t.Errorf("Unexpected page %+v", page)
+	}
+}
+
+func TestClient_Events(t *testing.T) {
+	since := time.Date(2018, 5, 1, 10, 0, 0, 0, time.UTC)
+	var requests []string
+	server := apiServer(t, map[string]interface{}{
+		"GET /api/v1/events?since=2018-05-01T10%3A00%3A00Z": []dashboard.Event{{Type: dashboard.EventBroken, Instance: "Release"}},
+	}, &requests)
+	defer server.Close()
+
+	events, err := New(server.URL).Events(context.Background(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != dashboard.EventBroken || events[0].Instance != "Release" {
+		t.Errorf("Unexpected events %+v", events)
+	}
+}
+
+func TestClient_Claims(t *testing.T) {
+	var requests []string
+	server := apiServer(t, map[string]interface{}{
+		"GET /api/v1/claims":                                []*dashboard.Claim{{Instance: "Release", Job: "folder/camunda-docs", User: "jane"}},
+		"POST /api/v1/claims/Release/folder/camunda-docs":   dashboard.Claim{Instance: "Release", Job: "folder/camunda-docs", User: "jane", Note: "on it"},
+		"DELETE /api/v1/claims/Release/folder/camunda-docs": nil,
+	}, &requests)
+	defer server.Close()
+
+	c := New(server.URL)
+	claims, err := c.Claims(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || claims[0].User != "jane" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+
+	claim, err := c.Claim(context.Background(), "Release", "folder/camunda-docs", "jane", "on it")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.User != "jane" || claim.Note != "on it" {
+		t.Errorf("Unexpected claim %+v", claim)
+	}
+	if expected := `POST /api/v1/claims/Release/folder/camunda-docs {"user":"jane","note":"on it"}`; requests[1] != expected {
+		t.Errorf("Expected request '%s', got '%s'", expected, requests[1])
+	}
+
+	if err := c.Release(context.Background(), "Release", "folder/camunda-docs"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Release(context.Background(), "Release", "unknown").(*httpclient.NotFoundError); !ok {
+		t.Error("Releasing an unknown claim should return a NotFoundError")
+	}
+}
+
+func TestClient_Canceled(t *testing.T) {
+	var requests []string
+	server := apiServer(t, map[string]interface{}{"GET /api/v1/claims": []*dashboard.Claim{}}, &requests)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(server.URL).Claims(ctx); err == nil {
+		t.Error("Requests should fail with a canceled context")
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"time"
+)
+
+const (
+	apiEndpoint = "/api/v1"
+
+	// defaultEventsPeriod is the period of the events served, if no start is requested.
+	defaultEventsPeriod = 24 * time.Hour
+)
+
+// initAPI registers the versioned API, whose contract is described by the OpenAPI document at /api/v1/openapi.json.
+// Claims and mutes are served by the same handlers as the legacy endpoints, but respond with errors as JSON objects.
+func initAPI(router *mux.Router) {
+	api := router.PathPrefix(apiEndpoint).Subrouter()
+
+	api.HandleFunc("/openapi.json", openAPIHandler).Methods(http.MethodGet)
+	api.HandleFunc("/summary", summaryHandler).Methods(http.MethodGet)
+	api.HandleFunc("/instances", instancesHandler).Methods(http.MethodGet)
+	api.HandleFunc("/jobs", jobsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/events", eventsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/claims", claimsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/claims/{instance}/{job:.+}", claimJobHandler(apiError)).Methods(http.MethodPost)
+	api.HandleFunc("/claims/{instance}/{job:.+}", releaseJobHandler(apiError)).Methods(http.MethodDelete)
+	api.HandleFunc("/mutes", mutesHandler).Methods(http.MethodGet)
+	api.HandleFunc("/mutes", muteHandler(apiError)).Methods(http.MethodPost)
+	api.HandleFunc("/mutes/{id}", unmuteHandler(apiError)).Methods(http.MethodDelete)
+}
+
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	spec, err := assetFS().Open("/openapi.json")
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer spec.Close()
+	body, err := ioutil.ReadAll(spec)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_, _ = w.Write(body)
+}
+
+// instancesHandler serves the instances matching the pattern given by 'instance', restricted to the jobs owned by
+// 'team'.
+func instancesHandler(w http.ResponseWriter, r *http.Request) {
+	pattern := r.URL.Query().Get("instance")
+	if _, err := path.Match(pattern, ""); err != nil {
+		apiError(w, http.StatusBadRequest, fmt.Sprintf("Invalid instance pattern '%s': %s", pattern, err))
+		return
+	}
+
+	instances := make([]*dashboard.InstanceAggregation, 0)
+	for _, aggregation := range brokenBoard.FetchFiltered(filterOf(r)) {
+		if matchesInstance(pattern, aggregation.Name) {
+			instances = append(instances, aggregation)
+		}
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(instances)
+}
+
+func jobsHandler(w http.ResponseWriter, r *http.Request) {
+	query, err := dashboard.ParseJobQuery(r.URL.Query())
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.Jobs(query))
+}
+
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-defaultEventsPeriod)
+	if value := r.URL.Query().Get("since"); value != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			apiError(w, http.StatusBadRequest, fmt.Sprintf("Invalid start time '%s', expected RFC 3339", value))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.Events(since))
+}
+
+// apiError responds with the given status and the message as JSON object.
+func apiError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{message})
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPI_RespondsWithJSONErrors(t *testing.T) {
+	claims, _ = dashboard.NewClaimStore("")
+	mutes, _ = dashboard.NewMuteStore("", []*dashboard.Mute{{Instance: "Release", Job: "*"}})
+	router := mux.NewRouter()
+	initAPI(router)
+
+	requests := []struct {
+		method     string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{http.MethodGet, "/api/v1/instances?instance=%5B", "", http.StatusBadRequest},
+		{http.MethodPost, "/api/v1/claims/Release/docs", "{}", http.StatusBadRequest},
+		{http.MethodDelete, "/api/v1/claims/Release/docs", "", http.StatusNotFound},
+		{http.MethodPost, "/api/v1/mutes", `{"instance": "Release", "job": "docs"}`, http.StatusBadRequest},
+		{http.MethodDelete, "/api/v1/mutes/unknown", "", http.StatusNotFound},
+		{http.MethodDelete, "/api/v1/mutes/config-1", "", http.StatusConflict},
+	}
+	for _, request := range requests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(request.method, request.path, strings.NewReader(request.body)))
+
+		var response struct {
+			Error string `json:"error"`
+		}
+		if recorder.Code != request.statusCode {
+			t.Errorf("%s %s should respond with %d, got %d", request.method, request.path, request.statusCode, recorder.Code)
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error == "" ||
+			recorder.Header().Get("Content-Type") != contentTypeJSON {
+			t.Errorf("%s %s should respond with a JSON error, got '%s'", request.method, request.path, recorder.Body.String())
+		}
+	}
+}
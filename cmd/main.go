@@ -7,31 +7,47 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"os/user"
+	"sort"
+	"strings"
 	"time"
 )
 
 const (
 	contentTypeJSON = "application/json"
 	cfgFileName     = ".camunda-ci-dashboard"
+
+	// exitConfig is the exit code of invalid configurations, which differs from the codes of the status command.
+	exitConfig = 4
 )
 
 type Config struct {
-	Jenkins     []*dashboard.JenkinsInstance
-	Travis      []*dashboard.TravisInstance
+	Instances   []dashboard.Provider
+	Sinks       []*dashboard.Sink
+	Digests     []*dashboard.DigestSchedule
 	Username    string
 	Password    string
 	Debug       bool
 	BindAddress string
+	ClaimsFile  string
+	MutesFile   string
+	Mutes       []*dashboard.Mute
+	Teams       []*dashboard.Team
+	Owners      []*dashboard.OwnershipRule
+	Boards      []*dashboard.Board
+	// PollInterval is the interval in which all instances are fetched, zero fetches them on every request instead.
+	PollInterval time.Duration
 }
 
 func (c *Config) String() string {
 	return fmt.Sprintf(
-		"Config{username:%s, password:%s, bindAddress:%s, debug:%t, jenkins:%+v}",
-		c.Username, c.Password, c.BindAddress, c.Debug, c.Jenkins)
+		"Config{username:%s, password:%s, bindAddress:%s, debug:%t, claimsFile:%s, mutesFile:%s, pollInterval:%s, instances:%+v}",
+		c.Username, c.Password, c.BindAddress, c.Debug, c.ClaimsFile, c.MutesFile, c.PollInterval, c.Instances)
 }
 
 var (
@@ -42,10 +58,26 @@ var (
 	dashboardEndpoint = "/dashboard"
 	jenkinsEndpoint   = dashboardEndpoint + "/jenkins"
 	travisEndpoint    = dashboardEndpoint + "/travis"
+	githubEndpoint    = dashboardEndpoint + "/github"
+	gitlabEndpoint    = dashboardEndpoint + "/gitlab"
+	claimsEndpoint    = dashboardEndpoint + "/claims"
+	mutesEndpoint     = dashboardEndpoint + "/mutes"
+	webhooksEndpoint  = dashboardEndpoint + "/webhooks"
+	boardsEndpoint    = dashboardEndpoint + "/boards"
+	summaryEndpoint   = dashboardEndpoint + "/summary"
+	boardsPage        = "/boards"
 	brokenBoard       *dashboard.Dashboard
+	claims            *dashboard.ClaimStore
+	mutes             *dashboard.MuteStore
 	config            *Config
 )
 
+// configFatalf logs the error of an invalid configuration and exits with exitConfig.
+func configFatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	os.Exit(exitConfig)
+}
+
 func homeDir() string {
 	usr, err := user.Current()
 	var homeDir string
@@ -60,7 +92,9 @@ func homeDir() string {
 	return homeDir
 }
 
-func readConfig() {
+// readConfig reads the config file and the environment, which are overridden by the given command line arguments.
+// Commands register their own flags before.
+func readConfig(args []string) {
 	viper.SetConfigName(cfgFileName)
 	viper.SetConfigType("json")
 	viper.AddConfigPath(".")
@@ -71,17 +105,26 @@ func readConfig() {
 	viper.SetDefault("password", "")
 	viper.SetDefault("bindAddress", "127.0.0.1:8000")
 	viper.SetDefault("debug", false)
+	viper.SetDefault("claimsFile", cfgFileName+"-claims.json")
+	viper.SetDefault("mutesFile", cfgFileName+"-mutes.json")
+	viper.SetDefault("pollInterval", dashboard.DefaultPollInterval.String())
 
 	// cmd line flags
 	pflag.String("bindAddress", "127.0.0.1:8000", "")
 	pflag.String("username", "", "")
 	pflag.String("password", "", "")
 	pflag.Bool("debug", false, "")
+	pflag.String("claimsFile", cfgFileName+"-claims.json", "")
+	pflag.String("mutesFile", cfgFileName+"-mutes.json", "")
+	pflag.String("pollInterval", dashboard.DefaultPollInterval.String(), "")
 	viper.BindPFlag("bindAddress", pflag.Lookup("bindAddress"))
 	viper.BindPFlag("username", pflag.Lookup("username"))
 	viper.BindPFlag("password", pflag.Lookup("password"))
 	viper.BindPFlag("debug", pflag.Lookup("debug"))
-	pflag.Parse()
+	viper.BindPFlag("claimsFile", pflag.Lookup("claimsFile"))
+	viper.BindPFlag("mutesFile", pflag.Lookup("mutesFile"))
+	viper.BindPFlag("pollInterval", pflag.Lookup("pollInterval"))
+	pflag.CommandLine.Parse(args)
 
 	// ENV vars
 	viper.SetEnvPrefix("ccd")
@@ -89,6 +132,9 @@ func readConfig() {
 	viper.BindEnv("password")
 	viper.BindEnv("bindAddress")
 	viper.BindEnv("debug")
+	viper.BindEnv("claimsFile")
+	viper.BindEnv("mutesFile")
+	viper.BindEnv("pollInterval")
 	viper.AutomaticEnv()
 
 	// evaluate
@@ -102,8 +148,25 @@ func readConfig() {
 		BindAddress: viper.GetString("bindAddress"),
 		Username:    viper.GetString("username"),
 		Password:    viper.GetString("password"),
-		Jenkins:     parseJenkinsInstanceConfig(),
-		Travis:      parseTravisInstanceConfig(),
+		ClaimsFile:  viper.GetString("claimsFile"),
+		MutesFile:   viper.GetString("mutesFile"),
+		Mutes:       parseMuteConfig(),
+	}
+	if err = viper.UnmarshalKey("teams", &config.Teams); err != nil {
+		configFatalf("Error while parsing teams config: %s", err)
+	}
+	if err = viper.UnmarshalKey("owners", &config.Owners); err != nil {
+		configFatalf("Error while parsing owners config: %s", err)
+	}
+	if err = viper.UnmarshalKey("boards", &config.Boards); err != nil {
+		configFatalf("Error while parsing boards config: %s", err)
+	}
+	config.Instances = parseInstanceConfig(config.Username, config.Password)
+	config.Sinks = parseNotificationConfig()
+	config.Digests = parseDigestConfig(config.Sinks)
+
+	if config.PollInterval, err = time.ParseDuration(viper.GetString("pollInterval")); err != nil {
+		configFatalf("Error while parsing poll interval: %s", err)
 	}
 
 	if config.Debug {
@@ -114,93 +177,300 @@ func readConfig() {
 	dashboard.Debug = config.Debug
 }
 
-func parseTravisInstanceConfig() []*dashboard.TravisInstance {
-	var travisInstances []*dashboard.TravisInstance
+func parseMuteConfig() []*dashboard.Mute {
+	var mutes []*dashboard.Mute
 
 	type config struct {
-		AccessToken   string
-		Organizations []struct {
-			Name  string
-			Repos []struct {
-				Name   string
-				Branch string
+		Instance string
+		Job      string
+		Until    string
+		Reason   string
+	}
+
+	var cfg []config
+	err := viper.UnmarshalKey("mutes", &cfg)
+	if err != nil {
+		configFatalf("Error while parsing mutes config: %s", err)
+	}
+
+	for _, m := range cfg {
+		mute := &dashboard.Mute{Instance: m.Instance, Job: m.Job, Reason: m.Reason}
+		if m.Until != "" {
+			until, err := time.Parse(time.RFC3339, m.Until)
+			if err != nil {
+				configFatalf("Error while parsing expiry time of mute for '%s/%s': %s", m.Instance, m.Job, err)
 			}
+			mute.Until = until
 		}
+		mutes = append(mutes, mute)
 	}
 
-	var cfg config
-	err := viper.UnmarshalKey("travis", &cfg)
-	if err != nil {
-		log.Fatalln("Error while parsing Travis config:", err)
+	return mutes
+}
+
+// parseNotificationConfig creates the sinks of the 'notifications' list, which are named by their type and
+// position unless they have a 'name'.
+func parseNotificationConfig() []*dashboard.Sink {
+	var configs []map[string]interface{}
+	if err := viper.UnmarshalKey("notifications", &configs); err != nil {
+		configFatalf("Error while parsing notifications config: %s", err)
 	}
 
-	for _, org := range cfg.Organizations {
-		if org.Name == "" {
-			continue
+	var sinks []*dashboard.Sink
+	for i, c := range configs {
+		name := stringOf(c, "name")
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", stringOf(c, "type"), i+1)
 		}
 
-		client := dashboard.NewTravisClient(dashboard.TravisApiUrl, cfg.AccessToken)
-		travisInstance := &dashboard.TravisInstance{Client: client, Name: org.Name}
+		sink, err := dashboard.NewSink(stringOf(c, "type"), name, c)
+		if err != nil {
+			configFatalf("Error while parsing notifications config: %s", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks
+}
 
-		for _, r := range org.Repos {
-			if r.Name == "" {
-				continue
-			}
+// parseDigestConfig creates the schedules of the 'digests' list, which are named by their position if unnamed.
+func parseDigestConfig(sinks []*dashboard.Sink) []*dashboard.DigestSchedule {
+	var configs []map[string]interface{}
+	if err := viper.UnmarshalKey("digests", &configs); err != nil {
+		configFatalf("Error while parsing digests config: %s", err)
+	}
+
+	var digests []*dashboard.DigestSchedule
+	for i, c := range configs {
+		name := stringOf(c, "name")
+		if name == "" {
+			name = fmt.Sprintf("digest-%d", i+1)
+		}
+
+		digest, err := dashboard.NewDigestSchedule(name, c, sinks)
+		if err != nil {
+			configFatalf("Error while parsing digests config: %s", err)
+		}
+		digests = append(digests, digest)
+	}
+	return digests
+}
 
-			branch := r.Branch
-			if branch == "" {
-				branch = "master"
+// parseInstanceConfig creates the providers of all instances, first the ones of the per type sections,
+// i.e. 'jenkins', 'travis', 'github' and 'gitlab', then the ones of the 'instances' list.
+func parseInstanceConfig(username string, password string) []dashboard.Provider {
+	type instance struct {
+		Type   string
+		Name   string
+		Config dashboard.ProviderConfig
+	}
+	var instances []instance
+
+	// Jenkins and GitLab instances are keyed by their name, Travis organizations and GitHub owners are listed
+	sections := []struct{ providerType, list string }{{"jenkins", ""}, {"travis", "organizations"}, {"github", "owners"}, {"gitlab", ""}}
+	for _, section := range sections {
+		if section.list != "" {
+			var configs []map[string]interface{}
+			if err := viper.UnmarshalKey(section.providerType+"."+section.list, &configs); err != nil {
+				configFatalf("Error while parsing %s config: %s", section.providerType, err)
 			}
+			for _, c := range configs {
+				if name := stringOf(c, "name"); name != "" {
+					instances = append(instances, instance{Type: section.providerType, Name: name, Config: c})
+				}
+			}
+			continue
+		}
 
-			travisInstance.Repos = append(travisInstance.Repos,
-				dashboard.TravisRepository{Organization: org.Name, Name: r.Name, Branch: branch})
+		configs := viper.GetStringMap(section.providerType)
+		names := make([]string, 0, len(configs))
+		for name := range configs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 
+		for _, name := range names {
+			c, ok := configs[name].(map[string]interface{})
+			if !ok {
+				configFatalf("Error while parsing %s config: instance '%s' is not an object", section.providerType, name)
+			}
+			instances = append(instances, instance{Type: section.providerType, Name: name, Config: c})
 		}
-		travisInstances = append(travisInstances, travisInstance)
 	}
 
-	return travisInstances
-}
+	var configs []map[string]interface{}
+	if err := viper.UnmarshalKey("instances", &configs); err != nil {
+		configFatalf("Error while parsing instances config: %s", err)
+	}
+	for _, c := range configs {
+		instances = append(instances, instance{Type: stringOf(c, "type"), Name: stringOf(c, "name"), Config: c})
+	}
 
-func parseJenkinsInstanceConfig() []*dashboard.JenkinsInstance {
-	var jenkinsInstances []*dashboard.JenkinsInstance
+	// credentials which are configured once for all instances of a type
+	defaults := map[string]map[string]string{
+		"jenkins": {"username": username, "password": password},
+		"travis":  {"accessToken": viper.GetString("travis.accessToken")},
+		"github":  {"accessToken": viper.GetString("github.accessToken")},
+	}
 
-	jenkins := viper.Get("jenkins")
-	if jenkins != nil {
-		jenkinsMap := jenkins.(map[string]interface{})
+	var providers []dashboard.Provider
+	for _, i := range instances {
+		for key, value := range defaults[i.Type] {
+			setDefault(i.Config, key, value)
+		}
 
-		for k, v := range jenkinsMap {
-			jenkinsInstance := new(dashboard.JenkinsInstance)
-			jenkinsInstance.Name = k
+		provider, err := dashboard.NewProvider(i.Type, i.Name, i.Config)
+		if err != nil {
+			configFatalf("Error while parsing instances config: %s", err)
+		}
+		providers = append(providers, provider)
+	}
 
-			url := v.(map[string]interface{})["url"].(string)
-			jenkinsInstance.Url = url
+	return providers
+}
 
-			publicUrl := v.(map[string]interface{})["publicurl"].(string)
-			jenkinsInstance.PublicUrl = publicUrl
+// stringOf returns the string value of the given key, which is matched case-insensitively as viper lowercases keys.
+func stringOf(config map[string]interface{}, key string) string {
+	for k, v := range config {
+		if strings.EqualFold(k, key) {
+			s, _ := v.(string)
+			return s
+		}
+	}
+	return ""
+}
 
-			if brokenJobsUrl, ok := v.(map[string]interface{})["brokenjobsurl"]; ok {
-				jenkinsInstance.BrokenJobsUrl = brokenJobsUrl.(string)
+// setDefault sets the given key to the value, unless the config already contains a non-empty value for it.
+func setDefault(config map[string]interface{}, key string, value string) {
+	if value == "" {
+		return
+	}
+	for k, v := range config {
+		if strings.EqualFold(k, key) {
+			if s, _ := v.(string); s != "" {
+				return
 			}
-
-			jenkinsInstances = append(jenkinsInstances, jenkinsInstance)
+			key = k
 		}
 	}
-
-	return jenkinsInstances
+	config[key] = value
 }
 
 func main() {
-	readConfig()
-	brokenBoard = dashboard.Init(config.Jenkins, config.Travis, config.Username, config.Password)
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve(args)
+	case "status":
+		os.Exit(status(args))
+	case "watch":
+		watch(args)
+	default:
+		log.Fatalf("[ERROR] Unknown command '%s', use 'serve', 'status' or 'watch'.", command)
+	}
+}
+
+// serve runs the dashboard server, which polls the instances and notifies the sinks.
+func serve(args []string) {
+	readConfig(args)
+
+	brokenBoard = newDashboard(config.Instances, false)
+	brokenBoard.UseSinks(config.Sinks...)
+	if config.PollInterval > 0 {
+		go brokenBoard.Poll(config.PollInterval, nil)
+	}
+	for _, digest := range config.Digests {
+		go brokenBoard.ScheduleDigest(digest, nil)
+	}
 	initServer(config.BindAddress)
 }
 
+// newDashboard validates the configuration and returns the dashboard of the given instances along with their claims,
+// mutes, owners and boards. Commands other than serve open the claims and mutes read-only, so their files are only
+// written by the server.
+func newDashboard(instances []dashboard.Provider, readOnly bool) *dashboard.Dashboard {
+	var err error
+	if readOnly {
+		claims, err = dashboard.NewReadOnlyClaimStore(config.ClaimsFile)
+	} else {
+		claims, err = dashboard.NewClaimStore(config.ClaimsFile)
+	}
+	if err != nil {
+		configFatalf("[ERROR] %s", err)
+	}
+
+	if readOnly {
+		mutes, err = dashboard.NewReadOnlyMuteStore(config.MutesFile, config.Mutes)
+	} else {
+		mutes, err = dashboard.NewMuteStore(config.MutesFile, config.Mutes)
+	}
+	if err != nil {
+		configFatalf("[ERROR] %s", err)
+	}
+
+	ownership, err := dashboard.NewOwnership(config.Teams, config.Owners)
+	if err != nil {
+		configFatalf("[ERROR] %s", err)
+	}
+
+	errs := dashboard.Validate(config.Instances)
+	for _, sink := range config.Sinks {
+		if err := sink.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, digest := range config.Digests {
+		if err := digest.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	boardNames := make(map[string]bool)
+	for _, board := range config.Boards {
+		if err := board.Validate(); err != nil {
+			errs = append(errs, err)
+		} else if boardNames[board.Name] {
+			errs = append(errs, fmt.Errorf("Board name '%s' is used twice.", board.Name))
+		}
+		boardNames[board.Name] = true
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Printf("[ERROR] %s", err)
+		}
+		configFatalf("[ERROR] Invalid configuration, found %d error(s).", len(errs))
+	}
+
+	d := dashboard.New(instances...)
+	d.UseClaims(claims)
+	d.UseMutes(mutes)
+	d.UseOwnership(ownership)
+	d.UseBoards(config.Boards...)
+	return d
+}
+
 func initServer(bindAddress string) {
 	router := mux.NewRouter()
 
+	router.HandleFunc(dashboardEndpoint, dashboardHandler).Methods(http.MethodGet)
+	router.HandleFunc(summaryEndpoint, summaryHandler).Methods(http.MethodGet)
 	router.HandleFunc(jenkinsEndpoint, jenkinsBoardHandler).Methods(http.MethodGet)
 	router.HandleFunc(travisEndpoint, travisBoardHandler).Methods(http.MethodGet)
+	router.HandleFunc(githubEndpoint, githubBoardHandler).Methods(http.MethodGet)
+	router.HandleFunc(gitlabEndpoint, gitlabBoardHandler).Methods(http.MethodGet)
+	router.HandleFunc(claimsEndpoint, claimsHandler).Methods(http.MethodGet)
+	router.HandleFunc(claimsEndpoint+"/{instance}/{job:.+}", claimJobHandler(textError)).Methods(http.MethodPost)
+	router.HandleFunc(claimsEndpoint+"/{instance}/{job:.+}", releaseJobHandler(textError)).Methods(http.MethodDelete)
+	router.HandleFunc(mutesEndpoint, mutesHandler).Methods(http.MethodGet)
+	router.HandleFunc(mutesEndpoint, muteHandler(textError)).Methods(http.MethodPost)
+	router.HandleFunc(mutesEndpoint+"/{id}", unmuteHandler(textError)).Methods(http.MethodDelete)
+	router.HandleFunc(webhooksEndpoint+"/{instance}", webhookHandler).Methods(http.MethodPost)
+	router.HandleFunc(boardsEndpoint, boardsHandler).Methods(http.MethodGet)
+	router.HandleFunc(boardsEndpoint+"/{name}", boardHandler).Methods(http.MethodGet)
+	router.HandleFunc(boardsPage+"/{name}", boardPageHandler).Methods(http.MethodGet)
+	initAPI(router)
 	router.PathPrefix("/static").Handler(http.StripPrefix("/static", http.FileServer(assetFS())))
 	router.Path("/").Handler(http.StripPrefix("/", http.FileServer(assetFS())))
 
@@ -220,12 +490,230 @@ func attachProfiler(router *mux.Router) {
 	router.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux)
 }
 
+func dashboardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.FetchFiltered(filterOf(r)))
+}
+
+// summaryHandler serves the totals of the instances, along with the instances themselves if requested by
+// 'aggregations=true'. Both are taken from the same fetch then, so the totals always match the jobs.
+func summaryHandler(w http.ResponseWriter, r *http.Request) {
+	aggregations := brokenBoard.FetchFiltered(filterOf(r))
+	summary := dashboard.Summarize(aggregations)
+	if r.URL.Query().Get("aggregations") == "true" {
+		summary.Aggregations = aggregations
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(summary)
+}
+
+// filterOf returns the filter given by the query parameters of the request, i.e. 'team'.
+func filterOf(r *http.Request) dashboard.Filter {
+	return dashboard.Filter{Team: r.URL.Query().Get("team")}
+}
+
 func travisBoardHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", contentTypeJSON)
-	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenTravisBuilds())
+	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenTravisBuilds(filterOf(r)))
+}
+
+func githubBoardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenGitHubBuilds(filterOf(r)))
+}
+
+func gitlabBoardHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenGitLabBuilds(filterOf(r)))
 }
 
 func jenkinsBoardHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", contentTypeJSON)
-	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenJenkinsBuilds())
+	_ = json.NewEncoder(w).Encode(brokenBoard.GetBrokenJenkinsBuilds(filterOf(r)))
+}
+
+func claimsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(claims.All())
+}
+
+// errorWriter responds with the given status and message, either as plain text for the endpoints of the frontend or
+// as JSON object for the versioned API.
+type errorWriter func(w http.ResponseWriter, statusCode int, message string)
+
+// textError responds with the given status and the message as plain text.
+func textError(w http.ResponseWriter, statusCode int, message string) {
+	http.Error(w, message, statusCode)
+}
+
+func claimJobHandler(writeError errorWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		var request struct {
+			User string `json:"user"`
+			Note string `json:"note"`
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err == nil {
+			err = json.Unmarshal(body, &request)
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid claim: %s", err))
+			return
+		}
+		if request.User == "" {
+			writeError(w, http.StatusBadRequest, "Invalid claim: user is missing")
+			return
+		}
+
+		claim, err := claims.Claim(vars["instance"], vars["job"], request.User, request.Note)
+		if err != nil {
+			log.Printf("[WARN] %s", err)
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(claim)
+	}
+}
+
+func releaseJobHandler(writeError errorWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+
+		released, err := claims.Release(vars["instance"], vars["job"])
+		if err != nil {
+			log.Printf("[WARN] %s", err)
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !released {
+			writeError(w, http.StatusNotFound, "Job is not claimed")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func mutesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(mutes.All())
+}
+
+func muteHandler(writeError errorWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Instance string    `json:"instance"`
+			Job      string    `json:"job"`
+			Until    time.Time `json:"until"`
+			Duration string    `json:"duration"`
+			Reason   string    `json:"reason"`
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err == nil {
+			err = json.Unmarshal(body, &request)
+		}
+		if err == nil && request.Duration != "" {
+			var duration time.Duration
+			if duration, err = time.ParseDuration(request.Duration); err == nil {
+				request.Until = time.Now().Add(duration)
+			}
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid mute: %s", err))
+			return
+		}
+
+		mute, err := mutes.Add(request.Instance, request.Job, request.Until, request.Reason)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid mute: %s", err))
+			return
+		}
+
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(mute)
+	}
+}
+
+func unmuteHandler(writeError errorWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		removed, err := mutes.Remove(mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if !removed {
+			writeError(w, http.StatusNotFound, "Mute not found")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func boardsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(brokenBoard.Boards())
+}
+
+func boardHandler(w http.ResponseWriter, r *http.Request) {
+	board := brokenBoard.FetchBoard(mux.Vars(r)["name"], filterOf(r))
+	if board == nil {
+		http.Error(w, "Board doesn't exist", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	_ = json.NewEncoder(w).Encode(board)
+}
+
+// boardPageHandler serves the page of the dashboard for a board. The page resolves its resources relative to the
+// root of the dashboard and loads the jobs of the board, as it's located below the boards page.
+func boardPageHandler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	for _, board := range brokenBoard.Boards() {
+		if board.Name != name {
+			continue
+		}
+
+		index, err := assetFS().Open("/index.html")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer index.Close()
+		page, err := ioutil.ReadAll(index)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(strings.Replace(string(page), "<head>", `<head>
+  <base href="../">`, 1)))
+		return
+	}
+	http.Error(w, "Board doesn't exist", http.StatusNotFound)
+}
+
+func webhookHandler(w http.ResponseWriter, r *http.Request) {
+	err := brokenBoard.ReceiveWebhook(mux.Vars(r)["instance"], r)
+	if webhookErr, ok := err.(*dashboard.WebhookError); ok {
+		log.Printf("[WARN] Rejected webhook: %s", webhookErr)
+		http.Error(w, webhookErr.Message, webhookErr.StatusCode)
+		return
+	}
+	if err != nil {
+		log.Printf("[WARN] %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
 }
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"github.com/spf13/pflag"
+	"io"
+	"log"
+	"os"
+	"path"
+	"text/tabwriter"
+)
+
+// The exit codes of the status command. Invalid flags exit with 2, invalid configurations with exitConfig.
+const (
+	exitOk          = 0
+	exitBroken      = 1
+	exitUnavailable = 3
+)
+
+const statusUsage = `Usage: %s status [flags]
+
+Prints the broken jobs of all instances and exits with
+  0  if no job is broken,
+  1  if a job is broken,
+  2  if a flag is invalid,
+  3  if no job is broken, but an instance couldn't be reached,
+  4  if the configuration is invalid.
+
+Flags:
+`
+
+// statusReport is the result of the status command, as printed with --json.
+type statusReport struct {
+	Broken int `json:"broken"`
+	// Unavailable are the selected instances which couldn't be reached at all.
+	Unavailable []dashboard.Aggregation `json:"unavailable"`
+	Jobs        []*dashboard.APIJob     `json:"jobs"`
+}
+
+// status fetches all instances once and prints the broken jobs selected by the flags, see statusUsage for its exit
+// codes. Muted jobs are neither printed nor counted, claims and mutes are only read.
+func status(args []string) int {
+	instance := pflag.String("instance", "", "only report the instances matching the pattern, e.g. 'release*'")
+	team := pflag.String("team", "", "only report the jobs owned by the team")
+	asJSON := pflag.Bool("json", false, "print the report as JSON instead of a table")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, statusUsage, os.Args[0])
+		pflag.PrintDefaults()
+	}
+	readConfig(args)
+	validateInstancePattern(*instance)
+
+	d := newDashboard(selectInstances(config.Instances, *instance), true)
+	report := newStatusReport(d.FetchFiltered(dashboard.Filter{Team: *team}))
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("[ERROR] %s", err)
+		}
+	} else {
+		report.print(os.Stdout)
+	}
+
+	return report.exitCode()
+}
+
+// validateInstancePattern exits if the pattern of the --instance flag is invalid.
+func validateInstancePattern(pattern string) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		configFatalf("[ERROR] Invalid instance pattern '%s': %s", pattern, err)
+	}
+}
+
+// matchesInstance returns true, if the name of the instance matches the pattern or the pattern is empty. The pattern
+// supports the same wildcards as mutes.
+func matchesInstance(pattern string, name string) bool {
+	return pattern == "" || dashboard.MatchPattern(pattern, name)
+}
+
+// selectInstances returns the instances matching the pattern, so that only these are fetched.
+func selectInstances(instances []dashboard.Provider, pattern string) []dashboard.Provider {
+	var selected []dashboard.Provider
+	for _, provider := range instances {
+		if matchesInstance(pattern, provider.Info().Name) {
+			selected = append(selected, provider)
+		}
+	}
+	return selected
+}
+
+// newStatusReport collects the broken jobs and the unavailable instances of the given aggregations.
+func newStatusReport(aggregations []*dashboard.InstanceAggregation) *statusReport {
+	report := &statusReport{Unavailable: make([]dashboard.Aggregation, 0), Jobs: make([]*dashboard.APIJob, 0)}
+	for _, aggregation := range aggregations {
+		if !aggregation.Health.IsAvailable() {
+			report.Unavailable = append(report.Unavailable, aggregation.Aggregation)
+		}
+	}
+
+	// jobs whose state couldn't be fetched are left out, the unavailability of their instance is reported instead
+	for _, job := range dashboard.SelectJobs(aggregations, dashboard.JobQuery{}) {
+		if job.Broken && !job.Unknown() {
+			report.Jobs = append(report.Jobs, job)
+		}
+	}
+	report.Broken = len(report.Jobs)
+	return report
+}
+
+// exitCode returns exitBroken if the report contains a broken job, exitUnavailable if it doesn't but an instance
+// couldn't be reached, and exitOk otherwise.
+func (r *statusReport) exitCode() int {
+	switch {
+	case r.Broken > 0:
+		return exitBroken
+	case len(r.Unavailable) > 0:
+		return exitUnavailable
+	}
+	return exitOk
+}
+
+// print writes the report as a table of the broken jobs, followed by the unavailable instances.
+func (r *statusReport) print(w io.Writer) {
+	if len(r.Jobs) == 0 {
+		fmt.Fprintln(w, "No broken jobs.")
+	} else {
+		table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(table, "INSTANCE\tJOB\tSTATUS\tOWNER\tCLAIMED BY")
+		for _, job := range r.Jobs {
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", job.Instance, job.Name, job.Status, ownerOf(job), claimantOf(job))
+		}
+		table.Flush()
+	}
+
+	for _, instance := range r.Unavailable {
+		fmt.Fprintf(w, "Instance '%s' is %s: %s\n", instance.Name, instance.Health.State, instance.Health.Error)
+	}
+}
+
+func ownerOf(job *dashboard.APIJob) string {
+	if job.Owner == nil {
+		return "-"
+	}
+	return job.Owner.Name
+}
+
+func claimantOf(job *dashboard.APIJob) string {
+	if job.Claim == nil {
+		return "-"
+	}
+	return job.Claim.User
+}
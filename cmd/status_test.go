@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"strings"
+	"testing"
+)
+
+func statusAggregations() []*dashboard.InstanceAggregation {
+	return []*dashboard.InstanceAggregation{
+		{
+			Aggregation: dashboard.Aggregation{Type: "jenkins", Name: "Release", Health: dashboard.Health{State: dashboard.HealthOk}},
+			Jobs: []dashboard.Job{
+				{ID: "docs", Name: "docs", Color: "red", Broken: true, Owner: &dashboard.Team{Name: "docs-team"}},
+				{ID: "website", Name: "website", Color: "yellow", Broken: true, Claim: &dashboard.Claim{User: "jane"}},
+				{ID: "deploy", Name: "deploy", Color: "blue"},
+			},
+			Muted: []dashboard.Job{{ID: "flaky", Name: "flaky", Color: "red", Broken: true}},
+		},
+		{
+			Aggregation: dashboard.Aggregation{Type: "jenkins", Name: "CI", Health: dashboard.Health{State: dashboard.HealthDown, Error: "timeout"}},
+		},
+	}
+}
+
+func TestNewStatusReport(t *testing.T) {
+	report := newStatusReport(statusAggregations())
+
+	if report.Broken != 2 || len(report.Jobs) != 2 || report.Jobs[0].ID != "docs" || report.Jobs[1].ID != "website" {
+		t.Fatalf("Report should contain the broken jobs which aren't muted, got %+v", report.Jobs)
+	}
+	if len(report.Unavailable) != 1 || report.Unavailable[0].Name != "CI" {
+		t.Fatalf("Report should contain the unavailable instances, got %+v", report.Unavailable)
+	}
+}
+
+func TestNewStatusReport_FailingInstance(t *testing.T) {
+	aggregations := []*dashboard.InstanceAggregation{{
+		Aggregation: dashboard.Aggregation{Type: "travis", Name: "camunda", Health: dashboard.Health{State: dashboard.HealthUnauthorized}},
+		Jobs: []dashboard.Job{
+			{ID: "repo1", Name: "repo1", Color: "grey", Broken: true, Error: "access denied"},
+			{ID: "repo2", Name: "repo2", Color: "grey", Error: "access denied"},
+		},
+	}}
+
+	report := newStatusReport(aggregations)
+
+	if report.Broken != 0 || len(report.Jobs) != 0 || len(report.Unavailable) != 1 {
+		t.Fatalf("Jobs of an unavailable instance shouldn't be reported as broken, got %+v", report)
+	}
+	if code := report.exitCode(); code != exitUnavailable {
+		t.Fatalf("Unavailable instance should exit with %d, got %d", exitUnavailable, code)
+	}
+}
+
+func TestStatusReport_Print(t *testing.T) {
+	var out bytes.Buffer
+	newStatusReport(statusAggregations()).print(&out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{
+		"INSTANCE  JOB      STATUS    OWNER      CLAIMED BY",
+		"Release   docs     failed    docs-team  -",
+		"Release   website  unstable  -          jane",
+		"Instance 'CI' is down: timeout",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Wrong report printed. Expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), out.String())
+	}
+
+	out.Reset()
+	newStatusReport(nil).print(&out)
+	if out.String() != "No broken jobs.\n" {
+		t.Fatalf("Empty report should say so, got '%s'", out.String())
+	}
+}
+
+func TestStatusReport_ExitCode(t *testing.T) {
+	aggregations := statusAggregations()
+	if code := newStatusReport(aggregations).exitCode(); code != exitBroken {
+		t.Errorf("Broken jobs should exit with %d, got %d", exitBroken, code)
+	}
+	if code := newStatusReport(aggregations[1:]).exitCode(); code != exitUnavailable {
+		t.Errorf("Unavailable instances should exit with %d, got %d", exitUnavailable, code)
+	}
+	if code := newStatusReport(nil).exitCode(); code != exitOk {
+		t.Errorf("Empty report should exit with %d, got %d", exitOk, code)
+	}
+}
+
+func TestMatchesInstance(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		matches bool
+	}{
+		{"", "Release", true},
+		{"Release", "Release", true},
+		{"rel*", "release", true},
+		{"rel*", "Release", false},
+		{"ci-?", "ci-1", true},
+		{"docs", "Release", false},
+		{"camunda*", "camunda/zeebe", true},
+	}
+	for _, c := range cases {
+		if matches := matchesInstance(c.pattern, c.name); matches != c.matches {
+			t.Errorf("Pattern '%s' should match '%s': %t, got %t", c.pattern, c.name, c.matches, matches)
+		}
+	}
+}
+
+func TestSelectInstances(t *testing.T) {
+	instances := []dashboard.Provider{&dashboard.JenkinsInstance{Name: "release"}, &dashboard.JenkinsInstance{Name: "ci"}}
+
+	selected := selectInstances(instances, "rel*")
+
+	if len(selected) != 1 || selected[0].Info().Name != "release" {
+		t.Fatalf("Only the matching instances should be selected, got %+v", selected)
+	}
+	if len(instances) != 2 || instances[1].Info().Name != "ci" {
+		t.Fatalf("Given instances shouldn't be modified, got %+v", instances)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences of the terminal UI.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiBlue    = "\x1b[34m"
+	ansiGray    = "\x1b[90m"
+
+	ansiHome           = "\x1b[H"
+	ansiClearLine      = "\x1b[K"
+	ansiClearScreen    = "\x1b[J"
+	ansiAlternate      = "\x1b[?1049h"
+	ansiMain           = "\x1b[?1049l"
+	ansiHideCursor     = "\x1b[?25l"
+	ansiShowCursor     = "\x1b[?25h"
+	defaultTermRows    = 24
+	defaultTermColumns = 80
+)
+
+// key is a key pressed in the terminal UI.
+type key int
+
+const (
+	keyNone key = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyBack
+	keyRefresh
+	keyQuit
+)
+
+// terminal is the controlling terminal in cbreak mode, i.e. keys are read without echo as soon as they are pressed,
+// while interrupts are still delivered as signals. The standard library has no terminal support, so the mode is
+// switched by stty.
+type terminal struct {
+	state string
+}
+
+// openTerminal switches the terminal into cbreak mode and shows the alternate screen, which keeps the scrollback
+// of the shell intact.
+func openTerminal() (*terminal, error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to access the terminal, watch requires an interactive terminal: %s", err)
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, fmt.Errorf("Unable to switch the terminal into cbreak mode: %s", err)
+	}
+
+	fmt.Fprint(os.Stdout, ansiAlternate+ansiHideCursor)
+	return &terminal{state: strings.TrimSpace(state)}, nil
+}
+
+// restore shows the main screen again and restores the previous mode of the terminal.
+func (t *terminal) restore() {
+	fmt.Fprint(os.Stdout, ansiShowCursor+ansiMain)
+	_, _ = stty(t.state)
+}
+
+// size returns the rows and columns of the terminal, or 24x80 if they are unknown.
+func (t *terminal) size() (int, int) {
+	var rows, columns int
+	out, err := stty("size")
+	if err == nil {
+		_, err = fmt.Sscan(out, &rows, &columns)
+	}
+	if err != nil || rows <= 0 || columns <= 0 {
+		return defaultTermRows, defaultTermColumns
+	}
+	return rows, columns
+}
+
+// draw replaces the screen with the given lines, which must fit the size of the terminal.
+func (t *terminal) draw(lines []string) {
+	var screen strings.Builder
+	screen.WriteString(ansiHome)
+	for i, line := range lines {
+		if i > 0 {
+			screen.WriteString("\n")
+		}
+		screen.WriteString(line + ansiReset + ansiClearLine)
+	}
+	screen.WriteString(ansiClearScreen)
+	fmt.Fprint(os.Stdout, screen.String())
+}
+
+// readKeys sends the keys pressed in the terminal to the channel until stdin is closed. Keys without a meaning in
+// the terminal UI are dropped.
+func readKeys(keys chan<- key) {
+	input := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(input)
+		if err != nil {
+			close(keys)
+			return
+		}
+		if k := parseKey(input[:n]); k != keyNone {
+			keys <- k
+		}
+	}
+}
+
+// parseKey maps the input of a single key press onto a key, including the escape sequences of the arrow and page keys.
+func parseKey(input []byte) key {
+	switch string(input) {
+	case "\x1b[A", "\x1bOA", "k":
+		return keyUp
+	case "\x1b[B", "\x1bOB", "j":
+		return keyDown
+	case "\x1b[5~", "b":
+		return keyPageUp
+	case "\x1b[6~", " ":
+		return keyPageDown
+	case "\r", "\n", "\x1b[C", "l":
+		return keyEnter
+	case "\x1b", "\x7f", "\x1b[D", "h":
+		return keyBack
+	case "r":
+		return keyRefresh
+	case "q", "\x03":
+		return keyQuit
+	}
+	return keyNone
+}
+
+// truncate shortens the text to the given number of characters, marking the cut by an ellipsis.
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	return string([]rune(text)[:width-1]) + "…"
+}
+
+func stty(args ...string) (string, error) {
+	command := exec.Command("stty", args...)
+	command.Stdin = os.Stdin
+	out, err := command.Output()
+	return string(out), err
+}
@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"github.com/camunda-ci/camunda-ci-dashboard/client"
+	"github.com/spf13/pflag"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// defaultWatchInterval is the interval in which the watch command refreshes the jobs by default.
+const defaultWatchInterval = 30 * time.Second
+
+// watchSource retrieves the instances shown by the watch command.
+type watchSource func() ([]*dashboard.InstanceAggregation, error)
+
+// watchResult is the result of a single refresh of the watch command.
+type watchResult struct {
+	instances []*dashboard.InstanceAggregation
+	err       error
+	time      time.Time
+}
+
+// watch shows the instances and their broken jobs in the terminal until it is quit. The jobs are either fetched
+// from the configured instances, whose claims and mutes are only read, or from the dashboard server given by --server.
+func watch(args []string) {
+	server := pflag.String("server", "", "watch the dashboard server at the URL instead of fetching the instances directly")
+	instance := pflag.String("instance", "", "only show the instances matching the pattern, e.g. 'release*'")
+	team := pflag.String("team", "", "only show the jobs owned by the team")
+	interval := pflag.Duration("interval", defaultWatchInterval, "the interval in which the jobs are refreshed")
+	readConfig(args)
+	validateInstancePattern(*instance)
+
+	var source watchSource
+	title := *server
+	if *server != "" {
+		c := client.New(*server)
+		source = func() ([]*dashboard.InstanceAggregation, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+			defer cancel()
+			instances, err := c.Instances(ctx, *team)
+			if err != nil {
+				return nil, err
+			}
+			var selected []*dashboard.InstanceAggregation
+			for _, aggregation := range instances {
+				if matchesInstance(*instance, aggregation.Name) {
+					selected = append(selected, aggregation)
+				}
+			}
+			return selected, nil
+		}
+	} else {
+		instances := selectInstances(config.Instances, *instance)
+		d := newDashboard(instances, true)
+		source = func() ([]*dashboard.InstanceAggregation, error) {
+			return d.FetchFiltered(dashboard.Filter{Team: *team}), nil
+		}
+		title = fmt.Sprintf("%d instance(s)", len(instances))
+	}
+	if *team != "" {
+		title += ", team " + *team
+	}
+
+	term, err := openTerminal()
+	if err != nil {
+		log.Fatalf("[ERROR] %s", err)
+	}
+	// the warnings of failing requests would garble the screen, the health of the instances is shown instead
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(os.Stderr)
+	defer term.restore()
+
+	runWatch(term, &watchView{title: title}, source, *interval)
+}
+
+// runWatch refreshes and draws the view until it is quit or interrupted.
+func runWatch(term *terminal, view *watchView, source watchSource, interval time.Duration) {
+	keys := make(chan key)
+	go readKeys(keys)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	results := make(chan watchResult, 1)
+	refresh := func() {
+		if view.refreshing {
+			return
+		}
+		view.refreshing = true
+		go func() {
+			instances, err := source()
+			results <- watchResult{instances: instances, err: err, time: time.Now()}
+		}()
+	}
+
+	// the screen is redrawn every second, which updates the time of the last refresh and follows resizes
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+	refreshes := time.NewTicker(interval)
+	defer refreshes.Stop()
+
+	refresh()
+	for {
+		term.draw(view.render(term.size()))
+
+		select {
+		case k, ok := <-keys:
+			if !ok || k == keyQuit {
+				return
+			}
+			if k == keyRefresh {
+				refresh()
+			} else {
+				view.press(k)
+			}
+		case result := <-results:
+			view.update(result)
+		case <-refreshes.C:
+			refresh()
+		case <-clock.C:
+		case <-signals:
+			return
+		}
+	}
+}
+
+// watchView is the state of the terminal UI, i.e. the instances along with their broken jobs and the selected job.
+type watchView struct {
+	title      string
+	instances  []*dashboard.InstanceAggregation
+	jobs       []*dashboard.APIJob
+	err        error
+	updated    time.Time
+	refreshing bool
+
+	selected int
+	// details is true, if the details of the selected job are shown instead of the list of jobs.
+	details bool
+	// top is the first line shown of the list or the details, which are scrolled to keep the selection visible.
+	top      int
+	pageSize int
+}
+
+// update replaces the instances by the result of a refresh, keeping the selected job selected.
+func (v *watchView) update(result watchResult) {
+	v.refreshing = false
+	v.err = result.err
+	if result.err != nil {
+		return
+	}
+
+	var selected *dashboard.APIJob
+	if v.selected < len(v.jobs) {
+		selected = v.jobs[v.selected]
+	}
+
+	v.instances = result.instances
+	v.updated = result.time
+	v.jobs = nil
+	for _, job := range dashboard.SelectJobs(result.instances, dashboard.JobQuery{Muted: true}) {
+		if job.Broken || job.Unknown() {
+			v.jobs = append(v.jobs, job)
+		}
+	}
+
+	for i, job := range v.jobs {
+		if selected != nil && job.Instance == selected.Instance && job.ID == selected.ID {
+			v.selected = i
+			return
+		}
+	}
+	if v.selected >= len(v.jobs) {
+		v.selected = len(v.jobs) - 1
+	}
+	if v.selected < 0 {
+		v.selected = 0
+	}
+	if len(v.jobs) == 0 {
+		v.details = false
+	}
+}
+
+// press moves the selection or opens and closes the details of the selected job. In the details, the up and down
+// keys scroll.
+func (v *watchView) press(k key) {
+	if v.details {
+		switch k {
+		case keyUp:
+			v.top--
+		case keyDown:
+			v.top++
+		case keyPageUp:
+			v.top -= v.pageSize
+		case keyPageDown:
+			v.top += v.pageSize
+		case keyBack:
+			v.details = false
+			v.top = 0
+		}
+		return
+	}
+
+	switch k {
+	case keyUp:
+		v.selected--
+	case keyDown:
+		v.selected++
+	case keyPageUp:
+		v.selected -= v.pageSize
+	case keyPageDown:
+		v.selected += v.pageSize
+	case keyEnter:
+		if len(v.jobs) > 0 {
+			v.details = true
+			v.top = 0
+		}
+	}
+	if v.selected >= len(v.jobs) {
+		v.selected = len(v.jobs) - 1
+	}
+	if v.selected < 0 {
+		v.selected = 0
+	}
+}
+
+// render returns the lines of the screen of the given size: a header, the list of jobs or the details of the
+// selected job, and the keys as footer.
+func (v *watchView) render(rows int, columns int) []string {
+	v.pageSize = rows - 3
+	if v.pageSize < 1 {
+		v.pageSize = 1
+	}
+
+	var body []styledLine
+	footer := "↑/↓ select · enter details · r refresh · q quit"
+	selectedLine := -1
+	if v.details && v.selected < len(v.jobs) {
+		body = jobDetails(v.jobs[v.selected])
+		footer = "↑/↓ scroll · esc back · r refresh · q quit"
+		if v.top > len(body)-v.pageSize {
+			v.top = len(body) - v.pageSize
+		}
+	} else {
+		body, selectedLine = v.jobList()
+		if selectedLine >= 0 && selectedLine < v.top {
+			v.top = selectedLine
+		}
+		if selectedLine >= v.top+v.pageSize {
+			v.top = selectedLine - v.pageSize + 1
+		}
+	}
+	if v.top < 0 {
+		v.top = 0
+	}
+
+	lines := []string{v.header(columns), ""}
+	for i := v.top; i < len(body) && i < v.top+v.pageSize; i++ {
+		style := body[i].style
+		if i == selectedLine {
+			style += ansiReverse
+		}
+		lines = append(lines, style+truncate(body[i].text, columns))
+	}
+	for len(lines) < rows-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, ansiDim+truncate(footer, columns))
+}
+
+// header summarizes the instances along with the time of the last refresh.
+func (v *watchView) header(columns int) string {
+	summary := dashboard.Summarize(v.instances)
+	text := fmt.Sprintf("CI Dashboard · %s · %d broken, %d muted", v.title, summary.Broken, summary.Muted)
+	if summary.Unknown > 0 {
+		text += fmt.Sprintf(", %d unknown", summary.Unknown)
+	}
+	if summary.InstancesDown > 0 {
+		text += fmt.Sprintf(", %d instance(s) down", summary.InstancesDown)
+	}
+
+	style := ansiBold
+	switch {
+	case v.err != nil:
+		text += " · " + v.err.Error()
+		style += ansiRed
+	case v.updated.IsZero():
+		text += " · loading…"
+	case v.refreshing:
+		text += " · refreshing…"
+	default:
+		text += fmt.Sprintf(" · updated %s ago", time.Since(v.updated).Truncate(time.Second))
+	}
+	return style + truncate(text, columns)
+}
+
+// styledLine is a line of text along with the ANSI escape sequences of its style.
+type styledLine struct {
+	text  string
+	style string
+}
+
+// jobList returns the lines of the instances, each followed by its broken jobs, along with the line of the
+// selected job.
+func (v *watchView) jobList() ([]styledLine, int) {
+	var lines []styledLine
+	selectedLine := -1
+	for _, instance := range v.instances {
+		line := styledLine{
+			text:  fmt.Sprintf("%s (%s) · %s", instance.Name, instance.Type, instance.Health.State),
+			style: ansiBold + healthStyle(instance.Health),
+		}
+		if instance.Health.Error != "" {
+			line.text += " · " + instance.Health.Error
+		}
+		lines = append(lines, line)
+
+		for i, job := range v.jobs {
+			if job.Instance != instance.Name {
+				continue
+			}
+			if i == v.selected {
+				selectedLine = len(lines)
+			}
+			lines = append(lines, styledLine{text: "  " + jobSummary(job), style: jobStyle(job)})
+		}
+	}
+	if len(v.instances) > 0 && len(v.jobs) == 0 {
+		lines = append(lines, styledLine{}, styledLine{text: "No broken jobs.", style: ansiGreen})
+	}
+	return lines, selectedLine
+}
+
+// jobSummary describes a job in a single line of the list.
+func jobSummary(job *dashboard.APIJob) string {
+	text := fmt.Sprintf("%-8s %s", job.Status, job.Name)
+	if job.Running {
+		text += " ⟳"
+	}
+	if job.FailCount > 0 {
+		text += fmt.Sprintf(" · %d failed test(s)", job.FailCount)
+	}
+	if job.Claim != nil {
+		text += " · claimed by " + job.Claim.User
+	}
+	if job.Muted {
+		text += " · muted"
+	}
+	return text
+}
+
+// jobDetails describes a job along with the details reported by its CI system, i.e. the failed tests and failure
+// causes of Jenkins jobs and the failed jobs of Travis builds, GitHub workflow runs and GitLab pipelines.
+func jobDetails(job *dashboard.APIJob) []styledLine {
+	lines := []styledLine{{text: job.Name, style: ansiBold + jobStyle(job)}, {}}
+	field := func(name string, value string) {
+		if value != "" {
+			lines = append(lines, styledLine{text: fmt.Sprintf("%-14s %s", name+":", value)})
+		}
+	}
+
+	field("Instance", fmt.Sprintf("%s (%s)", job.Instance, job.InstanceType))
+	field("Status", job.Status)
+	field("URL", job.URL)
+	if job.BrokenSince != nil {
+		field("Broken since", job.BrokenSince.Local().Format("2006-01-02 15:04"))
+	}
+	if job.Owner != nil {
+		field("Owner", job.Owner.Name)
+	}
+	if job.Claim != nil {
+		field("Claimed by", strings.TrimSpace(job.Claim.User+" "+job.Claim.Note))
+	}
+	if job.Mute != nil {
+		field("Muted", job.Mute.Reason)
+		if !job.Mute.Until.IsZero() {
+			field("Muted until", job.Mute.Until.Local().Format("2006-01-02 15:04"))
+		}
+	}
+	field("Error", job.Error)
+
+	list := func(title string, items []string) {
+		if len(items) > 0 {
+			lines = append(lines, styledLine{}, styledLine{text: title, style: ansiBold})
+			for _, item := range items {
+				lines = append(lines, styledLine{text: "  • " + item})
+			}
+		}
+	}
+
+	// the details of jobs received from a dashboard server are generic JSON objects, so they are decoded again
+	data, err := json.Marshal(job.Details)
+	if err != nil || job.Details == nil {
+		return lines
+	}
+	switch job.InstanceType {
+	case "jenkins":
+		var details dashboard.JenkinsJob
+		if json.Unmarshal(data, &details) != nil {
+			break
+		}
+		var causes []string
+		for _, action := range details.LastBuild.Actions {
+			if action.TotalCount > 0 {
+				field("Tests", fmt.Sprintf("%d failed, %d skipped, %d total", action.FailCount, action.SkipCount, action.TotalCount))
+			}
+			for _, cause := range action.FoundFailureCauses {
+				causes = append(causes, failureCause(cause))
+			}
+		}
+		list("Failure causes", causes)
+	case "travis":
+		var details dashboard.TravisJob
+		if json.Unmarshal(data, &details) != nil {
+			break
+		}
+		var failed []string
+		for _, matrixJob := range details.FailedJobs {
+			failed = append(failed, fmt.Sprintf("%s (%s) %s", matrixJob.Number, matrixJob.State, matrixJob.URL))
+		}
+		list("Failed jobs", failed)
+	case "github":
+		var details dashboard.GitHubJob
+		if json.Unmarshal(data, &details) != nil {
+			break
+		}
+		var failed []string
+		for _, failedJob := range details.FailedJobs {
+			failed = append(failed, fmt.Sprintf("%s (%s) %s", failedJob.Name, failedJob.Conclusion, failedJob.URL))
+		}
+		list("Failed jobs", failed)
+	case "gitlab":
+		var details dashboard.GitLabJob
+		if json.Unmarshal(data, &details) != nil {
+			break
+		}
+		var failed []string
+		for _, failedJob := range details.FailedJobs {
+			failed = append(failed, fmt.Sprintf("%s (%s) %s", failedJob.Name, failedJob.Stage, failedJob.URL))
+		}
+		list("Failed jobs", failed)
+	}
+	return lines
+}
+
+// failureCause describes a failure cause found by the Jenkins Build Failure Analyzer by its description and
+// categories.
+func failureCause(cause interface{}) string {
+	attributes, _ := cause.(map[string]interface{})
+	description, _ := attributes["description"].(string)
+	if description == "" {
+		description = "Unknown cause"
+	}
+
+	var categories []string
+	values, _ := attributes["categories"].([]interface{})
+	for _, value := range values {
+		if category, ok := value.(string); ok && category != "" {
+			categories = append(categories, category)
+		}
+	}
+	if len(categories) > 0 {
+		description += " [" + strings.Join(categories, ", ") + "]"
+	}
+	return description
+}
+
+// jobStyle colors a job like Jenkins colors its balls, muted jobs are dimmed.
+func jobStyle(job *dashboard.APIJob) string {
+	style := ""
+	if job.Muted {
+		style = ansiDim
+	}
+
+	switch {
+	case strings.HasPrefix(job.Color, "red"):
+		return style + ansiRed
+	case strings.HasPrefix(job.Color, "yellow"), strings.HasPrefix(job.Color, "amber"):
+		return style + ansiYellow
+	case strings.HasPrefix(job.Color, "blue"):
+		return style + ansiBlue
+	case strings.HasPrefix(job.Color, "green"):
+		return style + ansiGreen
+	}
+	return style + ansiGray
+}
+
+// healthStyle colors an instance by its health.
+func healthStyle(health dashboard.Health) string {
+	switch {
+	case health.IsOk():
+		return ansiGreen
+	case health.IsAvailable():
+		return ansiYellow
+	}
+	return ansiRed
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	dashboard "github.com/camunda-ci/camunda-ci-dashboard"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watchResultOf returns the result of a refresh of a single instance with broken jobs of the given IDs.
+func watchResultOf(ids ...string) watchResult {
+	aggregation := &dashboard.InstanceAggregation{
+		Aggregation: dashboard.Aggregation{Type: "jenkins", Name: "Release", Health: dashboard.Health{State: dashboard.HealthOk}},
+	}
+	for _, id := range ids {
+		aggregation.Jobs = append(aggregation.Jobs, dashboard.Job{ID: id, Name: id, Color: "red", Broken: true})
+	}
+	return watchResult{instances: []*dashboard.InstanceAggregation{aggregation}, time: time.Now()}
+}
+
+func selectedID(v *watchView) string {
+	if v.selected >= len(v.jobs) {
+		return ""
+	}
+	return v.jobs[v.selected].ID
+}
+
+func TestWatchView_Update(t *testing.T) {
+	view := &watchView{}
+	view.update(watchResultOf("docs", "website", "deploy"))
+	view.selected = 1
+
+	view.update(watchResultOf("release", "docs", "deploy", "website"))
+	if selectedID(view) != "website" {
+		t.Fatalf("Selected job should be kept across refreshes, got '%s'", selectedID(view))
+	}
+
+	view.update(watchResultOf("docs"))
+	if view.selected != 0 || selectedID(view) != "docs" {
+		t.Fatalf("Selection should be moved to the last job, if the selected one is gone, got %d", view.selected)
+	}
+
+	view.update(watchResult{err: fmt.Errorf("connection refused")})
+	if view.err == nil || len(view.jobs) != 1 {
+		t.Fatalf("Failed refresh should keep the jobs, got %+v", view.jobs)
+	}
+
+	view.details = true
+	view.update(watchResultOf())
+	if view.selected != 0 || view.details {
+		t.Fatalf("Details should be closed without jobs, got selection %d, details %t", view.selected, view.details)
+	}
+}
+
+func TestWatchView_Press_ClampsSelection(t *testing.T) {
+	view := &watchView{}
+	view.update(watchResultOf("docs", "website", "deploy"))
+
+	view.press(keyUp)
+	if view.selected != 0 {
+		t.Errorf("Selection shouldn't move above the first job, got %d", view.selected)
+	}
+
+	for i := 0; i < 5; i++ {
+		view.press(keyDown)
+	}
+	if view.selected != 2 {
+		t.Errorf("Selection shouldn't move below the last job, got %d", view.selected)
+	}
+}
+
+func TestWatchView_Press_ScrollsPages(t *testing.T) {
+	var ids []string
+	for i := 0; i < 20; i++ {
+		ids = append(ids, fmt.Sprintf("job-%02d", i))
+	}
+	view := &watchView{}
+	view.update(watchResultOf(ids...))
+
+	// 8 rows leave 5 lines for the list, the first of them shows the instance
+	lines := view.render(8, 80)
+	if len(lines) != 8 || view.pageSize != 5 {
+		t.Fatalf("Screen should have 8 lines and a page of 5 lines, got %d lines and a page of %d", len(lines), view.pageSize)
+	}
+
+	view.press(keyPageDown)
+	view.press(keyPageDown)
+	if selectedID(view) != "job-10" {
+		t.Fatalf("Page down should select the job a page below, got '%s'", selectedID(view))
+	}
+	lines = view.render(8, 80)
+	if view.top != 7 || !strings.Contains(lines[6], ansiReverse) || !strings.Contains(lines[6], "job-10") {
+		t.Fatalf("List should be scrolled to show the selection on its last line, got top %d and %q", view.top, lines)
+	}
+
+	view.press(keyPageUp)
+	view.render(8, 80)
+	if selectedID(view) != "job-05" || view.top != 6 {
+		t.Fatalf("Page up should select the job a page above and scroll to it, got '%s' and top %d", selectedID(view), view.top)
+	}
+
+	view.press(keyPageDown)
+	view.press(keyPageDown)
+	view.press(keyPageDown)
+	view.press(keyPageDown)
+	if selectedID(view) != "job-19" {
+		t.Fatalf("Page down should stop at the last job, got '%s'", selectedID(view))
+	}
+}
+
+func TestWatchView_Press_TogglesDetails(t *testing.T) {
+	view := &watchView{}
+	view.press(keyEnter)
+	if view.details {
+		t.Fatal("Details shouldn't be shown without jobs")
+	}
+
+	view.update(watchResultOf("docs", "website"))
+	view.press(keyDown)
+	view.press(keyEnter)
+	if !view.details {
+		t.Fatal("Enter should show the details of the selected job")
+	}
+	lines := view.render(24, 80)
+	if !strings.Contains(lines[2], "website") {
+		t.Fatalf("Details of the selected job should be shown, got %q", lines)
+	}
+
+	view.press(keyDown)
+	if view.selected != 1 || view.top != 1 {
+		t.Fatalf("Down should scroll the details instead of moving the selection, got selection %d, top %d", view.selected, view.top)
+	}
+
+	view.press(keyBack)
+	if view.details || view.top != 0 || view.selected != 1 {
+		t.Fatalf("Back should return to the list, got details %t, top %d, selection %d", view.details, view.top, view.selected)
+	}
+}
+
+func TestJobDetails_DecodesGenericDetails(t *testing.T) {
+	jenkinsJob := dashboard.JenkinsJob{Name: "docs", Color: "red"}
+	jenkinsJob.LastBuild.Actions = []dashboard.JenkinsAction{
+		{FailCount: 2, SkipCount: 1, TotalCount: 40},
+		{FoundFailureCauses: []interface{}{map[string]interface{}{"description": "Timeout", "categories": []string{"infra"}}}},
+	}
+	gitlabJob := dashboard.GitLabJob{Name: "zeebe", FailedJobs: []dashboard.GitLabFailedJob{{Name: "unit", Stage: "test", URL: "https://gitlab.example.com/jobs/1"}}}
+
+	cases := []struct {
+		instanceType string
+		details      interface{}
+		expected     []string
+	}{
+		{"jenkins", jenkinsJob, []string{"Tests:         2 failed, 1 skipped, 40 total", "Failure causes", "  • Timeout [infra]"}},
+		{"gitlab", gitlabJob, []string{"Failed jobs", "  • unit (test) https://gitlab.example.com/jobs/1"}},
+	}
+	for _, c := range cases {
+		// jobs received from a dashboard server hold their details as generic JSON objects
+		data, _ := json.Marshal(c.details)
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			t.Fatal(err)
+		}
+		job := &dashboard.APIJob{Instance: "Release", InstanceType: c.instanceType, Status: dashboard.StatusFailed,
+			Job: dashboard.Job{ID: "docs", Name: "docs", Broken: true, Details: generic}}
+
+		var texts []string
+		for _, line := range jobDetails(job) {
+			texts = append(texts, line.text)
+		}
+		text := strings.Join(texts, "\n")
+		for _, expected := range c.expected {
+			if !strings.Contains(text, expected) {
+				t.Errorf("Details of %s job should contain '%s', got:\n%s", c.instanceType, expected, text)
+			}
+		}
+	}
+}
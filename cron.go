@@ -0,0 +1,177 @@
+package dashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shortcuts for common cron expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonths   = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+	cronWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// CronSchedule is a schedule given by a cron expression with the five fields minute, hour, day of month, month
+// and day of week. Fields support lists, ranges, steps and the names of months and weekdays, e.g. '0 8 * * mon-fri'.
+// Like cron, a time matches if either the day of month or the day of week matches, if both are restricted.
+type CronSchedule struct {
+	expression string
+	minutes    []bool
+	hours      []bool
+	days       []bool
+	months     []bool
+	weekdays   []bool
+	// anyDay and anyWeekday are true, if the day of month respectively day of week starts with '*'.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCron parses a cron expression or one of the macros '@hourly', '@daily', '@weekly', '@monthly' and '@yearly'.
+func ParseCron(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if macro, ok := cronMacros[strings.ToLower(expression)]; ok {
+		fields = strings.Fields(macro)
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("Invalid cron expression '%s': expected 5 fields, got %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{expression: expression, anyDay: strings.HasPrefix(fields[2], "*"), anyWeekday: strings.HasPrefix(fields[4], "*")}
+	var err error
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("Invalid minute in cron expression '%s': %s", expression, err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("Invalid hour in cron expression '%s': %s", expression, err)
+	}
+	if schedule.days, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("Invalid day of month in cron expression '%s': %s", expression, err)
+	}
+	if schedule.months, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("Invalid month in cron expression '%s': %s", expression, err)
+	}
+	if schedule.weekdays, err = parseCronField(fields[4], 0, 7, cronWeekdays); err != nil {
+		return nil, fmt.Errorf("Invalid day of week in cron expression '%s': %s", expression, err)
+	}
+	// both 0 and 7 stand for sunday
+	schedule.weekdays[0] = schedule.weekdays[0] || schedule.weekdays[7]
+
+	return schedule, nil
+}
+
+// parseCronField returns the values between min and max matched by the field, indexed by the value.
+// Names are matched case-insensitively and stand for their index plus min.
+func parseCronField(field string, min int, max int, names []string) ([]bool, error) {
+	values := make([]bool, max+1)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step '%s'", part[i+1:])
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return nil, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseCronValue(bounds[1], min, max, names); err != nil {
+					return nil, err
+				}
+			} else if step > 1 {
+				// 'n/step' stands for every step starting at n
+				end = max
+			}
+			if end < start {
+				return nil, fmt.Errorf("invalid range '%s'", part)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+
+	return values, nil
+}
+
+func parseCronValue(s string, min int, max int, names []string) (int, error) {
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return i + min, nil
+		}
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("'%s' is not between %d and %d", s, min, max)
+	}
+	return value, nil
+}
+
+// Next returns the first time after the given one matching the schedule, in the location of the given time.
+// It returns the zero time, if no time matches within the next five years, e.g. for the 30th of February.
+func (s *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	day := s.days[t.Day()]
+	weekday := s.weekdays[int(t.Weekday())]
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
+
+func (s *CronSchedule) String() string {
+	return s.expression
+}
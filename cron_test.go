@@ -0,0 +1,63 @@
+package dashboard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	// a wednesday
+	after := time.Date(2018, time.May, 16, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		next       time.Time
+	}{
+		{"* * * * *", time.Date(2018, time.May, 16, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, time.May, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 8 * * *", time.Date(2018, time.May, 17, 8, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2018, time.May, 17, 10, 30, 0, 0, time.UTC)},
+		{"0 8 * * mon-fri", time.Date(2018, time.May, 17, 8, 0, 0, 0, time.UTC)},
+		{"0 8 * * SAT,7", time.Date(2018, time.May, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2018, time.May, 16, 13, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2018, time.May, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * *", time.Date(2018, time.May, 20, 0, 0, 0, 0, time.UTC)},
+		// day of month and day of week match either
+		{"0 0 20 * mon", time.Date(2018, time.May, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, time.May, 16, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2018, time.May, 17, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2018, time.May, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCron(test.expression)
+		if err != nil {
+			t.Errorf("Expression '%s' should be valid, got %s", test.expression, err)
+			continue
+		}
+		if next := schedule.Next(after); !next.Equal(test.next) {
+			t.Errorf("Wrong next time of '%s'. Expected: %s, got: %s", test.expression, test.next, next)
+		}
+	}
+}
+
+func TestParseCron_Location(t *testing.T) {
+	location := time.FixedZone("CEST", 2*60*60)
+	schedule, _ := ParseCron("0 8 * * *")
+
+	next := schedule.Next(time.Date(2018, time.May, 16, 7, 0, 0, 0, time.UTC).In(location))
+	if expected := time.Date(2018, time.May, 17, 6, 0, 0, 0, time.UTC); !next.Equal(expected) {
+		t.Fatalf("Schedule should be evaluated in the location of the given time. Expected: %s, got: %s", expected, next)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "* * * foo *", "*/0 * * * *", "10-5 * * * *", "@often"} {
+		if _, err := ParseCron(expression); err == nil {
+			t.Errorf("Expression '%s' should be invalid", expression)
+		}
+	}
+}
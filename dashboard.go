@@ -3,71 +3,440 @@ package dashboard
 import (
 	"fmt"
 	"log"
+	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 )
 
-const (
-	failed Status = false
-	ok     Status = true
-)
+// DefaultPollInterval is the interval in which the instances are polled by default.
+const DefaultPollInterval = time.Minute
 
-// Dashboard is a container for all configured JenkinsInstance's.
+// Dashboard is a container for all configured Provider's.
 type Dashboard struct {
-	jenkinsInstances []*JenkinsInstance
-	travisInstances  []*TravisInstance
+	providers []Provider
+	claims    *ClaimStore
+	mutes     *MuteStore
+	ownership *Ownership
+	boards    []*Board
+
+	lastSuccessesMutex sync.Mutex
+	lastSuccesses      map[string]*lastSuccess
+
+	// state holds the latest aggregation of every instance without claims and mutes, as fetched or updated by webhooks
+	stateMutex sync.RWMutex
+	state      map[string]*InstanceAggregation
+	polling    bool
+
+	// brokenJobs holds the broken jobs of every instance by their ID, to detect the transitions sent to the sinks
+	brokenJobs map[string]map[string]trackedJob
+	// history holds the transitions of the last days, which are summarized by the digests
+	history []Event
+	sinks   []*Sink
 }
 
-// status describes the current state of the instance.
-// 'ok', when the client was able to connect to it, or 'not available', when the connection was unsuccessful.
-type Status bool
-
 type Aggregation struct {
 	Name   string `json:"name"`
 	Url    string `json:"url"`
 	Type   string `json:"type"`
-	Status Status `json:"status"`
+	Health Health `json:"health"`
+}
+
+// New initializes the Dashboard with the given Provider's.
+func New(providers ...Provider) *Dashboard {
+	return &Dashboard{providers: providers}
+}
+
+// Validate checks the configuration of the given providers and returns a descriptive error for every misconfiguration.
+func Validate(providers []Provider) []error {
+	var errs []error
+	names := make(map[string]string)
+	for _, provider := range providers {
+		if err := provider.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		info := provider.Info()
+		if other, ok := names[info.Name]; ok {
+			errs = append(errs, fmt.Errorf("Instance name '%s' is used by a %s and a %s instance.", info.Name, other, info.Type))
+		}
+		names[info.Name] = info.Type
+	}
+	return errs
+}
+
+// AddProviders adds the given Provider's to the dashboard.
+func (d *Dashboard) AddProviders(providers ...Provider) {
+	d.providers = append(d.providers, providers...)
+}
+
+// UseClaims attaches the claims of the given ClaimStore to the broken jobs.
+// Claims of jobs which are not broken anymore are released automatically.
+func (d *Dashboard) UseClaims(claims *ClaimStore) {
+	d.claims = claims
+}
+
+// UseMutes moves the broken jobs matched by the mutes of the given MuteStore from the jobs to the muted jobs.
+func (d *Dashboard) UseMutes(mutes *MuteStore) {
+	d.mutes = mutes
+}
+
+// Filter selects the jobs served by the dashboard, the zero Filter selects all jobs.
+type Filter struct {
+	// Team selects the jobs owned by the team with the given name.
+	Team string
+}
+
+// apply removes the jobs and muted jobs of the aggregation which aren't selected by the filter.
+func (f Filter) apply(aggregation *InstanceAggregation) {
+	if f.Team == "" {
+		return
+	}
+
+	selected := func(jobs []Job) []Job {
+		filtered := make([]Job, 0, len(jobs))
+		for _, job := range jobs {
+			if job.Owner != nil && job.Owner.Name == f.Team {
+				filtered = append(filtered, job)
+			}
+		}
+		return filtered
+	}
+	aggregation.Jobs = selected(aggregation.Jobs)
+	aggregation.Muted = selected(aggregation.Muted)
+}
+
+// Fetch retrieves the broken jobs from all configured Provider's, in the order of their configuration.
+// While polling, the jobs of the latest poll are returned, including the updates received by webhooks since then.
+func (d *Dashboard) Fetch() []*InstanceAggregation {
+	return d.fetch(d.providers, Filter{})
+}
+
+// FetchFiltered retrieves the broken jobs selected by the filter from all configured Provider's, like Fetch.
+func (d *Dashboard) FetchFiltered(filter Filter) []*InstanceAggregation {
+	return d.fetch(d.providers, filter)
+}
+
+// fetch returns the broken jobs of the given providers selected by the filter, with the claims, mutes and owners
+// applied to them.
+func (d *Dashboard) fetch(providers []Provider, filter Filter) []*InstanceAggregation {
+	var aggregations []*InstanceAggregation
+	if d.isPolling() {
+		aggregations = d.stored(providers)
+	} else {
+		aggregations = d.refresh(providers)
+	}
+
+	for _, aggregation := range aggregations {
+		d.applyBrokenSince(aggregation)
+		d.applyClaims(aggregation)
+		d.applyMutes(aggregation)
+		d.applyOwners(aggregation)
+		filter.apply(aggregation)
+	}
+	return aggregations
+}
+
+// stored returns copies of the stored aggregations of the given providers. Providers which haven't been fetched
+// yet are fetched.
+func (d *Dashboard) stored(providers []Provider) []*InstanceAggregation {
+	aggregations := make([]*InstanceAggregation, len(providers))
+	var missing []Provider
+	var missingIndices []int
+
+	d.stateMutex.RLock()
+	for i, provider := range providers {
+		if aggregation, ok := d.state[provider.Info().Name]; ok {
+			aggregations[i] = aggregation.copy()
+		} else {
+			missing = append(missing, provider)
+			missingIndices = append(missingIndices, i)
+		}
+	}
+	d.stateMutex.RUnlock()
+
+	for i, aggregation := range d.refresh(missing) {
+		aggregations[missingIndices[i]] = aggregation
+	}
+	return aggregations
+}
+
+// refresh fetches the given providers concurrently, stores their aggregations and returns copies of them.
+func (d *Dashboard) refresh(providers []Provider) []*InstanceAggregation {
+	count := len(providers)
+	aggregations := make([]*InstanceAggregation, count)
+
+	var wg sync.WaitGroup
+	wg.Add(count)
+
+	for index, provider := range providers {
+		go func(ix int, p Provider) {
+			defer wg.Done()
+			aggregations[ix] = d.fetchProvider(p)
+		}(index, provider)
+	}
+
+	wg.Wait()
+	return aggregations
+}
+
+// fetchProvider retrieves the broken jobs of a single provider and stores them.
+func (d *Dashboard) fetchProvider(provider Provider) *InstanceAggregation {
+	var aggregation *InstanceAggregation
+
+	start := time.Now()
+	err := protect(func() error {
+		aggregation = provider.Fetch()
+		if aggregation.Jobs == nil {
+			aggregation.Jobs = make([]Job, 0)
+		}
+		if aggregation.Muted == nil {
+			aggregation.Muted = make([]Job, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		aggregation = &InstanceAggregation{
+			Aggregation: provider.Info(),
+			Jobs:        make([]Job, 0),
+			Muted:       make([]Job, 0),
+		}
+		aggregation.Health = failedHealth(err, time.Since(start))
+	}
+	d.lastSuccessOf(aggregation.Name).track(&aggregation.Health, time.Now())
+
+	d.stateMutex.Lock()
+	if d.state == nil {
+		d.state = make(map[string]*InstanceAggregation)
+	}
+	events := d.transitions(d.state[aggregation.Name], aggregation, time.Now())
+	d.state[aggregation.Name] = aggregation
+	d.stateMutex.Unlock()
+
+	d.notify(events)
+	return aggregation.copy()
+}
+
+// Poll fetches all instances in the given interval until stop is closed. After the first poll, the dashboard serves
+// the jobs of the latest poll instead of fetching the instances on every request.
+func (d *Dashboard) Poll(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.refresh(d.providers)
+		d.setPolling(true)
+
+		select {
+		case <-stop:
+			d.setPolling(false)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dashboard) isPolling() bool {
+	d.stateMutex.RLock()
+	defer d.stateMutex.RUnlock()
+
+	return d.polling
+}
+
+func (d *Dashboard) setPolling(polling bool) {
+	d.stateMutex.Lock()
+	defer d.stateMutex.Unlock()
+
+	d.polling = polling
+}
+
+// lastSuccessOf returns the time of the last successful fetch of the named instance.
+func (d *Dashboard) lastSuccessOf(name string) *lastSuccess {
+	d.lastSuccessesMutex.Lock()
+	defer d.lastSuccessesMutex.Unlock()
+
+	if d.lastSuccesses == nil {
+		d.lastSuccesses = make(map[string]*lastSuccess)
+	}
+	if _, ok := d.lastSuccesses[name]; !ok {
+		d.lastSuccesses[name] = &lastSuccess{}
+	}
+	return d.lastSuccesses[name]
+}
+
+// applyClaims attaches the claims to the jobs of the aggregation, falling back to the claims made in the CI system
+// itself. Claims of jobs which are not broken anymore are released, unless the instance couldn't be fetched
+// completely, as the jobs of the failed requests would be released otherwise.
+func (d *Dashboard) applyClaims(aggregation *InstanceAggregation) {
+	if d.claims == nil {
+		return
+	}
+
+	if aggregation.Health.IsOk() {
+		brokenJobs := make([]string, 0, len(aggregation.Jobs))
+		for _, job := range aggregation.Jobs {
+			// the claims of jobs whose state is unknown are kept, like the state itself
+			if job.Broken || job.Unknown() {
+				brokenJobs = append(brokenJobs, job.ID)
+			}
+		}
+		if err := d.claims.ReleaseFixed(aggregation.Name, brokenJobs); err != nil {
+			log.Printf("[WARN] %s", err)
+		}
+	}
+
+	// jobs whose state is unknown may be placeholders which don't identify a job, so they aren't claimed
+	for i := range aggregation.Jobs {
+		job := &aggregation.Jobs[i]
+		if job.Unknown() {
+			continue
+		}
+		if claim := d.claims.Get(aggregation.Name, job.ID); claim != nil {
+			job.Claim = claim
+		}
+	}
 }
 
-// Init initializes the Dashboard with the given JenkinsInstance's and how to access them.
-func Init(jenkinsInstances []*JenkinsInstance, travisInstances []*TravisInstance,
-	jenkinsUsername string, jenkinsPassword string) *Dashboard {
-	// initialize Jenkins clients
-	for _, jenkinsInstance := range jenkinsInstances {
-		jenkinsInstance.Client = NewJenkinsClient(jenkinsInstance.Url, jenkinsUsername, jenkinsPassword)
+// applyMutes moves the muted jobs of the aggregation to its muted jobs.
+func (d *Dashboard) applyMutes(aggregation *InstanceAggregation) {
+	if d.mutes == nil {
+		return
 	}
 
-	return &Dashboard{
-		jenkinsInstances: jenkinsInstances,
-		travisInstances:  travisInstances,
+	jobs := make([]Job, 0, len(aggregation.Jobs))
+	for _, job := range aggregation.Jobs {
+		if job.Mute = d.mutes.Find(aggregation.Name, job.ID); job.Mute != nil {
+			aggregation.Muted = append(aggregation.Muted, job)
+		} else {
+			jobs = append(jobs, job)
+		}
 	}
+	aggregation.Jobs = jobs
+}
+
+// GetBrokenJenkinsBuilds retrieves the failed builds displayed on the Broken page from all configured JenkinsInstance's
+// selected by the filter.
+func (d *Dashboard) GetBrokenJenkinsBuilds(filter Filter) []*JenkinsAggregation {
+	var instances []*JenkinsInstance
+	var providers []Provider
+	for _, provider := range d.providers {
+		if instance, ok := provider.(*JenkinsInstance); ok {
+			instances = append(instances, instance)
+			providers = append(providers, instance)
+		}
+	}
+
+	aggregations := make([]*JenkinsAggregation, len(instances))
+	for i, aggregation := range d.fetch(providers, filter) {
+		aggregations[i] = instances[i].aggregationOf(aggregation)
+	}
+	return aggregations
+}
+
+// GetBrokenTravisBuilds retrieves the failed builds from all configured TravisInstance's
+// selected by the filter.
+func (d *Dashboard) GetBrokenTravisBuilds(filter Filter) []*TravisAggregation {
+	var providers []Provider
+	for _, provider := range d.providers {
+		if instance, ok := provider.(*TravisInstance); ok {
+			providers = append(providers, instance)
+		}
+	}
+
+	aggregations := make([]*TravisAggregation, len(providers))
+	for i, aggregation := range d.fetch(providers, filter) {
+		aggregations[i] = travisAggregationOf(aggregation)
+	}
+	return aggregations
+}
+
+// GetBrokenGitHubBuilds retrieves the failed workflows from all configured GitHubInstance's
+// selected by the filter.
+func (d *Dashboard) GetBrokenGitHubBuilds(filter Filter) []*GitHubAggregation {
+	var providers []Provider
+	for _, provider := range d.providers {
+		if instance, ok := provider.(*GitHubInstance); ok {
+			providers = append(providers, instance)
+		}
+	}
+
+	aggregations := make([]*GitHubAggregation, len(providers))
+	for i, aggregation := range d.fetch(providers, filter) {
+		aggregations[i] = gitHubAggregationOf(aggregation)
+	}
+	return aggregations
+}
+
+// GetBrokenGitLabBuilds retrieves the failed pipelines from all configured GitLabInstance's
+// selected by the filter.
+func (d *Dashboard) GetBrokenGitLabBuilds(filter Filter) []*GitLabAggregation {
+	var providers []Provider
+	for _, provider := range d.providers {
+		if instance, ok := provider.(*GitLabInstance); ok {
+			providers = append(providers, instance)
+		}
+	}
+
+	aggregations := make([]*GitLabAggregation, len(providers))
+	for i, aggregation := range d.fetch(providers, filter) {
+		aggregations[i] = gitLabAggregationOf(aggregation)
+	}
+	return aggregations
 }
 
 func getBrokenBuildsForTravisInstance(instance *TravisInstance) *TravisAggregation {
-	count := len(instance.Repos)
+	start := time.Now()
+	configured, discoverErr := instance.repositories(start)
+	resolved, resolveErrs := resolveTravisBranches(instance.Client, configured)
+	count := len(resolved)
 	aggregation := &TravisAggregation{
 		Aggregation: Aggregation{
-			Type:   "travis",
-			Name:   instance.Name,
-			Url:    instance.Url(),
-			Status: ok,
+			Type: "travis",
+			Name: instance.Name,
+			Url:  instance.Url(),
 		},
-		Jobs: make([]TravisJob, count),
+		Jobs:  make([]TravisJob, count),
+		Muted: make([]TravisJob, 0),
 	}
 
 	var wg sync.WaitGroup
 	wg.Add(count)
 
-	for i, r := range instance.Repos {
+	// every repository records its error at its own index, so no locking is required
+	repos := make([]string, count)
+	errs := make([]error, count)
+	for i, r := range resolved {
 		go func(index int, repo TravisRepository) {
 			defer wg.Done()
-			job, _ := instance.Client.Job(repo)
-			aggregation.Jobs[index] = job
+
+			repos[index] = repo.Slug() + "@" + repo.Branch
+			errs[index] = protect(func() error {
+				if err, ok := resolveErrs[repo]; ok {
+					return err
+				}
+				job, err := instance.Client.Job(repo)
+				aggregation.Jobs[index] = job
+				return err
+			})
+			if errs[index] != nil && aggregation.Jobs[index].Error == "" {
+				aggregation.Jobs[index] = TravisJob{Name: repo.Name, Branch: repo.Branch, Color: "grey", Error: errs[index].Error()}
+			}
 		}(i, r)
 	}
 
 	wg.Wait()
 
+	if discoverErr != nil {
+		repos = append([]string{"discovery"}, repos...)
+		errs = append([]error{discoverErr}, errs...)
+	}
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("[WARN] %s (%s): %s", instance.Name, repos[i], err)
+		}
+	}
+	aggregation.Health = newRatioHealth(repos, errs, time.Since(start))
+
 	failedJobs := make([]TravisJob, 0)
 	for _, job := range aggregation.Jobs {
 		if !job.IsSuccessful() {
@@ -79,116 +448,224 @@ func getBrokenBuildsForTravisInstance(instance *TravisInstance) *TravisAggregati
 	return aggregation
 }
 
-func (d *Dashboard) GetBrokenTravisBuilds() []*TravisAggregation {
-	count := len(d.travisInstances)
-	aggregations := make([]*TravisAggregation, count)
+func getBrokenBuildsForGitHubInstance(instance *GitHubInstance) *GitHubAggregation {
+	start := time.Now()
+	count := len(instance.Repos)
+	aggregation := &GitHubAggregation{
+		Aggregation: Aggregation{
+			Type: "github",
+			Name: instance.Name,
+			Url:  instance.Url(),
+		},
+		Muted: make([]GitHubJob, 0),
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(count)
 
-	for index, instance := range d.travisInstances {
-		go func(ix int, i *TravisInstance) {
+	// every repository records its jobs and error at its own index, so no locking is required
+	repos := make([]string, count)
+	jobs := make([][]GitHubJob, count)
+	errs := make([]error, count)
+	for i, r := range instance.Repos {
+		go func(index int, repo GitHubRepository) {
 			defer wg.Done()
-			aggregations[ix] = getBrokenBuildsForTravisInstance(i)
-		}(index, instance)
+
+			repos[index] = repo.Slug()
+			if repo.Branch != "" {
+				repos[index] += "@" + repo.Branch
+			}
+			errs[index] = protect(func() error {
+				var err error
+				jobs[index], err = instance.Client.Jobs(repo)
+				return err
+			})
+			if errs[index] != nil && len(jobs[index]) == 0 {
+				jobs[index] = []GitHubJob{{Name: repo.Name, Branch: repo.Branch, Color: "grey", Error: errs[index].Error()}}
+			}
+		}(i, r)
 	}
 
 	wg.Wait()
-	return aggregations
+
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("[WARN] %s (%s): %s", instance.Name, repos[i], err)
+		}
+	}
+	aggregation.Health = newRatioHealth(repos, errs, time.Since(start))
+
+	aggregation.Jobs = make([]GitHubJob, 0)
+	for _, repoJobs := range jobs {
+		for _, job := range repoJobs {
+			if !job.IsSuccessful() {
+				aggregation.Jobs = append(aggregation.Jobs, job)
+			}
+		}
+	}
+
+	return aggregation
 }
 
-// GetBrokenJenkinsBuilds retrieves the failed builds displayed on the Broken page from all configured JenkinsInstance's.
-func (d *Dashboard) GetBrokenJenkinsBuilds() []*JenkinsAggregation {
-	count := len(d.jenkinsInstances)
-	jenkinsAggregations := make([]*JenkinsAggregation, count)
+func getBrokenBuildsForGitLabInstance(instance *GitLabInstance) *GitLabAggregation {
+	start := time.Now()
+	count := len(instance.Projects)
+	aggregation := &GitLabAggregation{
+		Aggregation: Aggregation{
+			Type: "gitlab",
+			Name: instance.Name,
+			Url:  instance.Url,
+		},
+		Jobs:  make([]GitLabJob, count),
+		Muted: make([]GitLabJob, 0),
+	}
 
 	var wg sync.WaitGroup
 	wg.Add(count)
 
-	for index, jenkinsInstance := range d.jenkinsInstances {
-		go func(instance *JenkinsInstance, index int) {
+	// every project records its error at its own index, so no locking is required
+	projects := make([]string, count)
+	errs := make([]error, count)
+	for i, p := range instance.Projects {
+		go func(index int, project GitLabProject) {
 			defer wg.Done()
-			jenkinsAggregations[index] = getBrokenBuildsForJenkinsInstance(instance)
-		}(jenkinsInstance, index)
+
+			projects[index] = project.Path
+			if project.Ref != "" {
+				projects[index] += "@" + project.Ref
+			}
+			errs[index] = protect(func() error {
+				job, err := instance.Client.Job(project)
+				aggregation.Jobs[index] = job
+				return err
+			})
+			if errs[index] != nil && aggregation.Jobs[index].Error == "" {
+				aggregation.Jobs[index] = GitLabJob{Name: project.Path, Ref: project.Ref, Color: "grey", Error: errs[index].Error()}
+			}
+			instance.resolveDefaultRef(project, &aggregation.Jobs[index])
+		}(i, p)
 	}
 
 	wg.Wait()
-	return jenkinsAggregations
-}
 
-func getBrokenBuildsForJenkinsInstance(instance *JenkinsInstance) *JenkinsAggregation {
-	if instance.BrokenJobsUrl == "" {
-		instance.BrokenJobsUrl = instance.Url
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("[WARN] %s (%s): %s", instance.Name, projects[i], err)
+		}
+	}
+	aggregation.Health = newRatioHealth(projects, errs, time.Since(start))
+
+	failedJobs := make([]GitLabJob, 0)
+	for _, job := range aggregation.Jobs {
+		if !job.IsSuccessful() {
+			failedJobs = append(failedJobs, job)
+		}
 	}
+	aggregation.Jobs = failedJobs
+
+	return aggregation
+}
 
+func getBrokenBuildsForJenkinsInstance(instance *JenkinsInstance) *JenkinsAggregation {
+	start := time.Now()
 	jenkinsAggregation := &JenkinsAggregation{
 		Aggregation: Aggregation{
-			Type:   "jenkins",
-			Name:   instance.Name,
-			Url:    instance.Url,
-			Status: ok,
+			Type: "jenkins",
+			Name: instance.Name,
+			Url:  instance.Url,
 		},
-		BrokenJobsUrl: instance.BrokenJobsUrl,
+		BrokenJobsUrl: instance.brokenJobsUrl(),
 		PublicUrl:     instance.PublicUrl,
+		Jobs:          make([]JenkinsJob, 0),
+		Muted:         make([]JenkinsJob, 0),
 	}
 
+	// every sub-request records its error at its own index, so no locking is required.
+	// The sub-requests are ordered by their importance for the health of the instance.
+	requests := []string{"view", "queue", "executors"}
+	errs := make([]error, len(requests))
+
 	var wg sync.WaitGroup
-	wg.Add(3)
+	wg.Add(len(requests))
 
 	go func(instance *JenkinsInstance, aggregation *JenkinsAggregation) {
 		defer wg.Done()
 
-		queue, err := instance.Client.GetQueue()
-		if err != nil {
-			log.Printf("[WARN] %s", err)
-			aggregation.BuildQueueSize = 0
-			aggregation.Status = failed
-			return
-		}
-		aggregation.BuildQueueSize = len(queue.Items)
-
+		errs[1] = protect(func() error {
+			queue, err := instance.Client.GetQueue()
+			if err != nil {
+				return err
+			}
+			aggregation.BuildQueueSize = len(queue.Items)
+			return nil
+		})
 	}(instance, jenkinsAggregation)
 
 	go func(instance *JenkinsInstance, aggregation *JenkinsAggregation) {
 		defer wg.Done()
 
-		currentBusyExecutors, err := instance.Client.GetBusyExecutors()
-		if err != nil {
-			log.Printf("[WARN] %s", err)
-			aggregation.BusyExecutors = 0
-			aggregation.Status = failed
-			return
-		}
-		aggregation.BusyExecutors = currentBusyExecutors
-
+		errs[2] = protect(func() error {
+			currentBusyExecutors, err := instance.Client.GetBusyExecutors()
+			if err != nil {
+				return err
+			}
+			aggregation.BusyExecutors = currentBusyExecutors
+			return nil
+		})
 	}(instance, jenkinsAggregation)
 
 	go func(instance *JenkinsInstance, aggregation *JenkinsAggregation) {
 		defer wg.Done()
 
-		tree := "jobs[name,fullDisplayName,color,url,lastBuild[actions[foundFailureCauses[categories,description],failCount,skipCount,totalCount]]]"
-
-		path := getBrokenJobsPath(instance)
-		jobs, err := instance.Client.GetJobsFromViewWithTreeByPath(path+"/view/Broken", tree)
-		if err != nil {
-			log.Printf("[WARN] %s", err)
-			aggregation.Jobs = make([]JenkinsJob, 0)
-			aggregation.Status = failed
-			return
-		}
-		aggregation.Jobs = jobs
-
+		errs[0] = protect(func() error {
+			tree := "jobs[name,fullName,fullDisplayName,color,url,lastBuild[actions[foundFailureCauses[categories,description],failCount,skipCount,totalCount,claimed,claimedBy,reason]]]"
+
+			path, err := getBrokenJobsPath(instance)
+			if err != nil {
+				return err
+			}
+			jobs, err := instance.Client.GetJobsFromViewWithTreeByPath(path+"/view/Broken", tree)
+			if err != nil {
+				return err
+			}
+			aggregation.Jobs = jobs
+			return nil
+		})
 	}(instance, jenkinsAggregation)
 
 	wg.Wait()
 
+	for i, err := range errs {
+		if err != nil {
+			log.Printf("[WARN] %s (%s): %s", instance.Name, requests[i], err)
+		}
+	}
+	jenkinsAggregation.Health = newHealth(requests, errs, time.Since(start))
+
 	return jenkinsAggregation
 }
 
-func getBrokenJobsPath(instance *JenkinsInstance) string {
-	if strings.HasPrefix(instance.BrokenJobsUrl, instance.Url) {
-		return strings.TrimPrefix(instance.BrokenJobsUrl, instance.Url)
+func isJenkinsJobSuccessful(job JenkinsJob) bool {
+	return strings.HasPrefix(job.Color, "blue")
+}
+
+func getBrokenJobsPath(instance *JenkinsInstance) (string, error) {
+	brokenJobsUrl := instance.brokenJobsUrl()
+	if strings.HasPrefix(brokenJobsUrl, instance.Url) {
+		return strings.TrimPrefix(brokenJobsUrl, instance.Url), nil
 	}
 
-	panic(fmt.Sprintf("Instance URL '%s' must be part of broken jobs URL '%s'.", instance.Url, instance.BrokenJobsUrl))
+	return "", fmt.Errorf("Instance URL '%s' must be part of broken jobs URL '%s'.", instance.Url, brokenJobsUrl)
+}
+
+// protect calls f and converts a panic into an error, so a single misbehaving instance can't take down the whole server.
+func protect(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Recovered from panic: %v", r)
+			log.Printf("[ERROR] %s\n%s", err, debug.Stack())
+		}
+	}()
+
+	return f()
 }
@@ -3,8 +3,14 @@ package dashboard
 import (
 	"errors"
 	"log"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"github.com/shuheiktgw/go-travis"
 )
 
 const (
@@ -13,7 +19,7 @@ const (
 
 func TestDashboard_GetBrokenTravisBuilds(t *testing.T) {
 	i := createDashboardInstanceWithSingleTravisInstance()
-	res := i.GetBrokenTravisBuilds()
+	res := i.GetBrokenTravisBuilds(Filter{})
 	expAggrs := 1
 
 	if len(res) != expAggrs {
@@ -21,15 +27,20 @@ func TestDashboard_GetBrokenTravisBuilds(t *testing.T) {
 			expAggrs, len(res))
 	}
 
+	if res[0].Health.State != HealthOk || res[0].Health.LastSuccess == nil {
+		log.Fatalf("Wrong health returned. Expected state ok with last success, got %+v", res[0].Health)
+	}
+	res[0].Health = Health{}
+
 	expAggr := TravisAggregation{
 		Aggregation: Aggregation{
-			Name:   "camunda",
-			Url:    "https://travis-ci.org/camunda",
-			Type:   "travis",
-			Status: true},
+			Name: "camunda",
+			Url:  "https://travis-ci.com/camunda",
+			Type: "travis"},
 		Jobs: []TravisJob{
-			{Name: "repo1", URL: "https://github.com/org/repo1", Color: "red"},
+			{Name: "repo1", Branch: "master", URL: "https://github.com/org/repo1", Color: "red"},
 		}, // only broken jobs returned
+		Muted: []TravisJob{},
 	}
 
 	if !reflect.DeepEqual(*res[0], expAggr) {
@@ -41,7 +52,7 @@ func TestDashboard_GetBrokenTravisBuilds(t *testing.T) {
 func TestDashboard_GetBrokenJenkinsBuilds_Happy(t *testing.T) {
 	instance := createDashboardInstanceWithSingleJenkinsInstance()
 
-	brokenJenkinsBuilds := instance.GetBrokenJenkinsBuilds()
+	brokenJenkinsBuilds := instance.GetBrokenJenkinsBuilds(Filter{})
 	if len(brokenJenkinsBuilds) != 1 {
 		t.Fatalf("Wrong number of jenkins aggregations returned. Expected 1, but got %d", len(brokenJenkinsBuilds))
 	}
@@ -64,13 +75,16 @@ func TestDashboard_GetBrokenJenkinsBuilds_JenkinsClientReturnsError(t *testing.T
 
 	instance := createDashboardInstanceWithCustomJenkinsClient([]*JenkinsInstance{jenkinsInstance}, client)
 
-	brokenJenkinsBuilds := instance.GetBrokenJenkinsBuilds()
+	brokenJenkinsBuilds := instance.GetBrokenJenkinsBuilds(Filter{})
 
 	if len(brokenJenkinsBuilds) != 1 {
 		t.Fatalf("Wrong number of jenkins aggregations returned. Expected 1, but got %d", len(brokenJenkinsBuilds))
 	}
-	if brokenJenkinsBuilds[0].Status != failed {
-		t.Fatal("status should be set to 'not available' in case of errors.")
+	if brokenJenkinsBuilds[0].Health.State != HealthDown {
+		t.Fatal("health should be set to 'down' in case of errors.")
+	}
+	if brokenJenkinsBuilds[0].Health.Error != "timeout" || brokenJenkinsBuilds[0].Health.FailedRequest != "view" {
+		t.Fatalf("health should describe the failing request, got %+v", brokenJenkinsBuilds[0].Health)
 	}
 
 	for _, brokenJenkinsBuild := range brokenJenkinsBuilds {
@@ -81,6 +95,537 @@ func TestDashboard_GetBrokenJenkinsBuilds_JenkinsClientReturnsError(t *testing.T
 	}
 }
 
+func TestDashboard_GetBrokenJenkinsBuilds_AttachesClaims(t *testing.T) {
+	instance := createDashboardInstanceWithSingleJenkinsInstance()
+	client := instance.providers[0].(*JenkinsInstance).Client.(*TestJenkinsClient)
+
+	claimedByPlugin := JenkinsJob{Name: "plugin", Color: "red"}
+	claimedByPlugin.LastBuild.Actions = []JenkinsAction{{Claimed: true, ClaimedBy: "john", Reason: "infrastructure"}}
+	client.jobs = []JenkinsJob{
+		{Name: "master", FullName: "docs/master", Color: "red"},
+		claimedByPlugin,
+	}
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("Jenkins Public", "docs/master", "jane", "on it")
+	claims.Claim("Jenkins Public", "fixed", "jane", "")
+	instance.UseClaims(claims)
+
+	jobs := instance.GetBrokenJenkinsBuilds(Filter{})[0].Jobs
+
+	if jobs[0].Claim == nil || jobs[0].Claim.User != "jane" {
+		t.Fatalf("Dashboard claim should be attached to job, got %+v", jobs[0].Claim)
+	}
+	if jobs[1].Claim == nil || jobs[1].Claim.User != "john" || jobs[1].Claim.Source != claimSourceJenkins {
+		t.Fatalf("Claim plugin claim should be attached to job, got %+v", jobs[1].Claim)
+	}
+	if claims.Get("Jenkins Public", "fixed") != nil {
+		t.Fatal("Claim of a job which isn't broken anymore should have been released.")
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_KeepsClaimsOnError(t *testing.T) {
+	instance := createDashboardInstanceWithMocks([]*JenkinsInstance{
+		{Name: "Jenkins Public", Url: fixtureJenkinsUrl, BrokenJobsUrl: fixtureJenkinsUrl},
+	}, nil, true, errors.New("timeout"))
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("Jenkins Public", "docs/master", "jane", "on it")
+	instance.UseClaims(claims)
+
+	instance.GetBrokenJenkinsBuilds(Filter{})
+
+	if claims.Get("Jenkins Public", "docs/master") == nil {
+		t.Fatal("Claims must not be released when the instance is not available.")
+	}
+}
+
+func TestDashboard_Fetch_AttachesClaimsOfDegradedInstance(t *testing.T) {
+	provider := &TestProvider{name: "Release", jobs: []Job{{ID: "docs", Color: "red", Broken: true}}, health: HealthDegraded}
+	instance := New(provider)
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("Release", "docs", "jane", "on it")
+	claims.Claim("Release", "website", "jane", "")
+	instance.UseClaims(claims)
+
+	jobs := instance.Fetch()[0].Jobs
+
+	if jobs[0].Claim == nil || jobs[0].Claim.User != "jane" {
+		t.Fatalf("Claim should be attached to the job of a degraded instance, got %+v", jobs[0].Claim)
+	}
+	if claims.Get("Release", "website") == nil {
+		t.Fatal("Claims must not be released when the instance is degraded.")
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_AttachesClaims(t *testing.T) {
+	instance := createDashboardInstanceWithSingleTravisInstance()
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("camunda", "repo1", "jane", "")
+	claims.Claim("camunda", "repo2@feature", "jane", "")
+	instance.UseClaims(claims)
+
+	jobs := instance.GetBrokenTravisBuilds(Filter{})[0].Jobs
+
+	if jobs[0].Claim == nil || jobs[0].Claim.User != "jane" {
+		t.Fatalf("Claim should be attached to job, got %+v", jobs[0].Claim)
+	}
+	if claims.Get("camunda", "repo2@feature") != nil {
+		t.Fatal("Claim of the green job should have been released.")
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_SeparatesMutedJobs(t *testing.T) {
+	instance := createDashboardInstanceWithSingleJenkinsInstance()
+	client := instance.providers[0].(*JenkinsInstance).Client.(*TestJenkinsClient)
+	client.jobs = []JenkinsJob{
+		{Name: "camunda-bpm-platform", Color: "red"},
+		{Name: "docs", Color: "red"},
+	}
+
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "Jenkins Public", Job: "camunda-bpm-*", Reason: "outage"}})
+	instance.UseMutes(mutes)
+
+	aggregation := instance.GetBrokenJenkinsBuilds(Filter{})[0]
+
+	if len(aggregation.Jobs) != 1 || aggregation.Jobs[0].Name != "docs" {
+		t.Fatalf("Only unmuted jobs should be returned as jobs, got %+v", aggregation.Jobs)
+	}
+	if len(aggregation.Muted) != 1 || aggregation.Muted[0].Mute == nil || aggregation.Muted[0].Mute.Reason != "outage" {
+		t.Fatalf("Muted jobs should be returned separately, got %+v", aggregation.Muted)
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_SeparatesMutedJobs(t *testing.T) {
+	instance := createDashboardInstanceWithSingleTravisInstance()
+
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "camunda", Job: "repo1*"}})
+	instance.UseMutes(mutes)
+
+	aggregation := instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if len(aggregation.Jobs) != 0 {
+		t.Fatalf("Muted job should not be returned as job, got %+v", aggregation.Jobs)
+	}
+	if len(aggregation.Muted) != 1 || aggregation.Muted[0].Name != "repo1" {
+		t.Fatalf("Muted job should be returned separately, got %+v", aggregation.Muted)
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_MisconfiguredBrokenJobsUrl(t *testing.T) {
+	instance := createDashboardInstanceWithMocks([]*JenkinsInstance{
+		{Name: "Docs", Url: fixtureJenkinsUrl, BrokenJobsUrl: "http://other.jenkins.io/job/docs"},
+	}, nil, true, nil)
+
+	aggregation := instance.GetBrokenJenkinsBuilds(Filter{})[0]
+
+	if aggregation.Health.State != HealthDegraded || aggregation.Health.FailedRequest != "view" {
+		t.Fatalf("health should be set to 'degraded' for a misconfigured view, got %+v", aggregation.Health)
+	}
+	if !strings.Contains(aggregation.Health.Error, "must be part of broken jobs URL") {
+		t.Fatalf("Error should describe the misconfiguration, got '%s'", aggregation.Health.Error)
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_RecoversFromPanic(t *testing.T) {
+	instance := createDashboardInstanceWithSingleJenkinsInstance()
+	instance.providers = append([]Provider{
+		&JenkinsInstance{Name: "Panicking", Url: fixtureJenkinsUrl, Client: &PanickingJenkinsClient{}},
+	}, instance.providers...)
+
+	aggregations := instance.GetBrokenJenkinsBuilds(Filter{})
+
+	if aggregations[0].Health.IsOk() || !strings.Contains(aggregations[0].Health.Error, "panic") {
+		t.Fatalf("Panic should be converted into an error state, got %+v", aggregations[0])
+	}
+	if !aggregations[1].Health.IsOk() {
+		t.Fatalf("Other instances must not be affected by a panic, got %+v", aggregations[1])
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_RecoversFromPanic(t *testing.T) {
+	travisInstance := &TravisInstance{
+		Name:   "camunda",
+		Client: &PanickingTravisClient{},
+		Repos:  []TravisRepository{{Organization: "camunda", Name: "repo", Branch: "master"}},
+	}
+	instance := New(travisInstance)
+
+	aggregation := instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if aggregation.Health.IsOk() || !strings.Contains(aggregation.Health.Error, "panic") {
+		t.Fatalf("Panic should be converted into an error state, got %+v", aggregation)
+	}
+	if len(aggregation.Jobs) != 1 || aggregation.Jobs[0].Color != "grey" {
+		t.Fatalf("Repository should be reported as unknown, got %+v", aggregation.Jobs)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	providers := []Provider{
+		&JenkinsInstance{Name: "Release", Url: fixtureJenkinsUrl},
+		&JenkinsInstance{Name: "Docs", Url: fixtureJenkinsUrl, BrokenJobsUrl: fixtureJenkinsUrl + "/job/docs"},
+		&JenkinsInstance{Name: "Misconfigured", Url: fixtureJenkinsUrl, BrokenJobsUrl: "http://other.jenkins.io"},
+		&JenkinsInstance{Name: "NoUrl"},
+		&JenkinsInstance{Name: "NoScheme", Url: "ci.jenkins.io"},
+		&TravisInstance{Name: "camunda", Client: &TestTravisClient{}},
+		&TravisInstance{Name: "", Client: &TestTravisClient{}},
+	}
+
+	errs := Validate(providers)
+
+	if len(errs) != 4 {
+		t.Fatalf("Wrong number of errors returned. Expected 4, got %d: %v", len(errs), errs)
+	}
+	for i, instance := range []string{"Misconfigured", "NoUrl", "NoScheme"} {
+		if !strings.Contains(errs[i].Error(), instance) {
+			t.Errorf("Error should name the instance '%s', got '%s'", instance, errs[i])
+		}
+	}
+}
+
+func TestValidate_DuplicateNames(t *testing.T) {
+	providers := []Provider{
+		&TravisInstance{Name: "camunda", Client: &TestTravisClient{}},
+		&GitHubInstance{Name: "camunda", Client: &TestGitHubClient{}},
+	}
+
+	errs := Validate(providers)
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "'camunda' is used by a travis and a github instance") {
+		t.Fatalf("Instance names should be unique, got %v", errs)
+	}
+}
+
+func TestDashboard_Fetch(t *testing.T) {
+	instance := createDashboardInstanceWithSingleTravisInstance()
+	instance.AddProviders(&TestProvider{name: "Test", jobs: []Job{
+		{ID: "deploy", Color: "red", Broken: true},
+		{ID: "docs", Color: "green"},
+	}})
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("Test", "deploy", "jane", "")
+	claims.Claim("Test", "docs", "john", "")
+	instance.UseClaims(claims)
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "camunda", Job: "repo1*"}})
+	instance.UseMutes(mutes)
+
+	aggregations := instance.Fetch()
+
+	if len(aggregations) != 2 || aggregations[0].Type != "travis" || aggregations[1].Type != "test" {
+		t.Fatalf("Aggregations should be returned in the order of the providers, got %+v", aggregations)
+	}
+	travisAggregation := aggregations[0]
+	if len(travisAggregation.Jobs) != 0 || len(travisAggregation.Muted) != 1 || travisAggregation.Muted[0].ID != "repo1" {
+		t.Fatalf("Muted job should be moved to the muted jobs, got %+v", travisAggregation)
+	}
+	if _, ok := travisAggregation.Muted[0].Details.(TravisJob); !ok {
+		t.Fatalf("Details should hold the TravisJob, got %T", travisAggregation.Muted[0].Details)
+	}
+	testAggregation := aggregations[1]
+	if testAggregation.Jobs[0].Claim == nil || testAggregation.Jobs[0].Claim.User != "jane" {
+		t.Fatalf("Claim should be attached to the broken job, got %+v", testAggregation.Jobs[0])
+	}
+	if claims.Get("Test", "docs") != nil {
+		t.Fatal("Claim of the successful job should be released")
+	}
+	if testAggregation.Health.LastSuccess == nil || testAggregation.Muted == nil {
+		t.Fatalf("Time of last success and muted jobs should be set, got %+v", testAggregation)
+	}
+}
+
+func TestDashboard_Fetch_RecoversFromPanic(t *testing.T) {
+	instance := New(&TestProvider{name: "Panicking", panics: true}, &TestProvider{name: "Test"})
+
+	aggregations := instance.Fetch()
+
+	if aggregations[0].Health.IsOk() || !strings.Contains(aggregations[0].Health.Error, "panic") {
+		t.Fatalf("Panic should be converted into an error state, got %+v", aggregations[0])
+	}
+	if aggregations[0].Name != "Panicking" || aggregations[0].Jobs == nil || !aggregations[1].Health.IsOk() {
+		t.Fatalf("Instance should be described by its info, got %+v", aggregations)
+	}
+}
+
+func TestDashboard_Poll(t *testing.T) {
+	provider := &TestProvider{name: "Test", jobs: []Job{{ID: "deploy", Color: "red", Broken: true}}}
+	instance := New(provider)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go instance.Poll(time.Hour, stop)
+
+	for i := 0; i < 100 && !instance.isPolling(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !instance.isPolling() {
+		t.Fatal("Dashboard should poll the instances")
+	}
+
+	provider.jobs = nil
+	if jobs := instance.Fetch()[0].Jobs; len(jobs) != 1 || jobs[0].ID != "deploy" {
+		t.Fatalf("Jobs of the last poll should be returned, got %+v", jobs)
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_Health(t *testing.T) {
+	tests := []struct {
+		err           error
+		expectedState HealthState
+	}{
+		{nil, HealthOk},
+		{errors.New("connection refused"), HealthDown},
+		{&client.UnauthorizedError{Message: "Authentication required."}, HealthUnauthorized},
+		{&client.NotFoundError{Message: "Resource not found."}, HealthNotFound},
+		{&client.HttpError{Message: "Forbidden", StatusCode: 403}, HealthUnauthorized},
+		{&client.HttpError{Message: "Bad Gateway", StatusCode: 502}, HealthDown},
+	}
+
+	for _, test := range tests {
+		instance := createDashboardInstanceWithMocks([]*JenkinsInstance{
+			{Name: "Jenkins Public", Url: fixtureJenkinsUrl},
+		}, nil, true, test.err)
+
+		health := instance.GetBrokenJenkinsBuilds(Filter{})[0].Health
+
+		if health.State != test.expectedState {
+			t.Errorf("Wrong health state for error '%v'. Expected %s, got %s", test.err, test.expectedState, health.State)
+		}
+		if test.err == nil && health.LastSuccess == nil {
+			t.Errorf("Time of last success should be set.")
+		}
+		if test.err != nil && health.LastSuccess != nil {
+			t.Errorf("Time of last success must not be set without a successful fetch.")
+		}
+	}
+}
+
+func TestDashboard_GetBrokenJenkinsBuilds_KeepsLastSuccess(t *testing.T) {
+	instance := createDashboardInstanceWithSingleJenkinsInstance()
+	lastSuccess := instance.GetBrokenJenkinsBuilds(Filter{})[0].Health.LastSuccess
+
+	instance.providers[0].(*JenkinsInstance).Client.(*TestJenkinsClient).error = errors.New("timeout")
+	health := instance.GetBrokenJenkinsBuilds(Filter{})[0].Health
+
+	if health.State != HealthDown {
+		t.Fatalf("Wrong health state. Expected %s, got %s", HealthDown, health.State)
+	}
+	if health.LastSuccess == nil || !health.LastSuccess.Equal(*lastSuccess) {
+		t.Fatalf("Time of last success should be kept. Expected %v, got %v", lastSuccess, health.LastSuccess)
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_Health(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, travis.ApiComUrl, nil)
+	revoked := &travis.ErrorResponse{
+		Response:     &http.Response{StatusCode: http.StatusForbidden, Request: request},
+		ErrorMessage: "access denied",
+	}
+
+	instance := createDashboardInstanceWithSingleTravisInstance()
+	travisInstance := instance.providers[0].(*TravisInstance)
+	client := travisInstance.Client.(*TestTravisClient)
+
+	client.errors = map[TravisRepository]error{travisInstance.Repos[1]: errors.New("repository not found")}
+	aggregation := instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if aggregation.Health.State != HealthDegraded || aggregation.Health.FailedRequest != "org/repo2@feature" {
+		t.Fatalf("Instance should be degraded if some repositories fail, got %+v", aggregation.Health)
+	}
+	if len(aggregation.Jobs) != 2 || aggregation.Jobs[1].Error != "repository not found" {
+		t.Fatalf("Failing repository should be returned with its error, got %+v", aggregation.Jobs)
+	}
+
+	client.errors = map[TravisRepository]error{travisInstance.Repos[0]: revoked, travisInstance.Repos[1]: revoked}
+	aggregation = instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if aggregation.Health.State != HealthUnauthorized {
+		t.Fatalf("Instance should be unauthorized if all repositories are denied, got %+v", aggregation.Health)
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_ResolvesBranchPatterns(t *testing.T) {
+	instance := createDashboardInstanceWithSingleTravisInstance()
+	travisInstance := instance.providers[0].(*TravisInstance)
+	client := travisInstance.Client.(*TestTravisClient)
+
+	pattern := TravisRepository{Organization: "org", Name: "repo1", Branch: "release/*"}
+	release := TravisRepository{Organization: "org", Name: "repo1", Branch: "release/7.12"}
+	travisInstance.Repos = append(travisInstance.Repos, pattern)
+	client.branches = map[TravisRepository][]string{pattern: {"master", "release/7.12", "feature/release"}}
+	client.jobs[release] = TravisJob{Name: "repo1", Branch: "release/7.12", Color: "red"}
+
+	jobs := instance.GetBrokenTravisBuilds(Filter{})[0].Jobs
+
+	if len(jobs) != 2 || jobs[1].ID() != "repo1@release/7.12" {
+		t.Fatalf("Matching branches should be returned as separate jobs, got %+v", jobs)
+	}
+
+	client.errors = map[TravisRepository]error{pattern: errors.New("branches not available")}
+	aggregation := instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if aggregation.Health.State != HealthDegraded || aggregation.Health.FailedRequest != "org/repo1@release/*" {
+		t.Fatalf("Instance should be degraded if a pattern can't be resolved, got %+v", aggregation.Health)
+	}
+}
+
+func TestDashboard_GetBrokenTravisBuilds_DiscoversRepositories(t *testing.T) {
+	instance := createDashboardInstanceWithSingleTravisInstance()
+	travisInstance := instance.providers[0].(*TravisInstance)
+	client := travisInstance.Client.(*TestTravisClient)
+
+	discovered := TravisRepository{Organization: "camunda", Name: "repo3", Branch: "master"}
+	client.repositories = []string{"repo3"}
+	client.jobs[discovered] = TravisJob{Name: "repo3", Branch: "master", Color: "red"}
+	travisInstance.Discovery, _ = NewTravisDiscovery("", "", nil, 0)
+
+	aggregation := instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if len(aggregation.Jobs) != 2 || aggregation.Jobs[1].Name != "repo3" {
+		t.Fatalf("Discovered repository should be watched along the configured ones, got %+v", aggregation.Jobs)
+	}
+
+	travisInstance.Discovery, _ = NewTravisDiscovery("", "", nil, 0)
+	client.error = errors.New("owner not found")
+	aggregation = instance.GetBrokenTravisBuilds(Filter{})[0]
+
+	if aggregation.Health.FailedRequest != "discovery" || aggregation.Health.State != HealthDown {
+		t.Fatalf("Failing discovery should be part of the health, got %+v", aggregation.Health)
+	}
+}
+
+func TestDashboard_GetBrokenGitHubBuilds(t *testing.T) {
+	client := &TestGitHubClient{
+		jobs: map[string][]GitHubJob{
+			"camunda/zeebe": {
+				{Name: "zeebe", Workflow: "CI", Branch: "main", Color: "red"},
+				{Name: "zeebe", Workflow: "Docs", Branch: "main", Color: "green"},
+			},
+		},
+		errors: map[string]error{"camunda/operate": errors.New("workflow runs not available")},
+	}
+	instance := New(&GitHubInstance{
+		Name:   "camunda",
+		Client: client,
+		Repos:  []GitHubRepository{{Owner: "camunda", Name: "zeebe"}, {Owner: "camunda", Name: "operate"}},
+	})
+
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "camunda", Job: "operate*"}})
+	instance.UseMutes(mutes)
+
+	aggregation := instance.GetBrokenGitHubBuilds(Filter{})[0]
+
+	if len(aggregation.Jobs) != 1 || aggregation.Jobs[0].ID() != "zeebe/CI@main" {
+		t.Fatalf("Only broken workflows should be returned, got %+v", aggregation.Jobs)
+	}
+	if len(aggregation.Muted) != 1 || aggregation.Muted[0].Error != "workflow runs not available" {
+		t.Fatalf("Failing repository should be returned with its error, got %+v", aggregation.Muted)
+	}
+	if id := aggregation.Muted[0].ID(); id != "operate" {
+		t.Fatalf("Failing repository on its default branch should be identified by the repository, got '%s'", id)
+	}
+	if aggregation.Health.State != HealthDegraded || aggregation.Health.FailedRequest != "camunda/operate" {
+		t.Fatalf("Instance should be degraded if some repositories fail, got %+v", aggregation.Health)
+	}
+}
+
+func TestDashboard_Fetch_GitHubPlaceholderIsNotClaimed(t *testing.T) {
+	instance := New(&GitHubInstance{
+		Name:   "camunda",
+		Client: &TestGitHubClient{errors: map[string]error{"camunda/zeebe": errors.New("access denied")}},
+		Repos:  []GitHubRepository{{Owner: "camunda", Name: "zeebe", Branch: "stable"}},
+	})
+	claims, _ := NewClaimStore("")
+	claims.Claim("camunda", "zeebe@stable", "jane", "")
+	instance.UseClaims(claims)
+
+	jobs := instance.Fetch()[0].Jobs
+
+	if len(jobs) != 1 || jobs[0].ID != "zeebe@stable" || !jobs[0].Unknown() {
+		t.Fatalf("Failing repository should be identified by the repository and its branch, got %+v", jobs)
+	}
+	if jobs[0].Claim != nil {
+		t.Fatalf("Placeholder of a failing repository shouldn't be claimed, got %+v", jobs[0].Claim)
+	}
+}
+
+func TestDashboard_GetBrokenGitLabBuilds(t *testing.T) {
+	client := &TestGitLabClient{
+		jobs: map[GitLabProject]GitLabJob{
+			{Path: "camunda/optimize", Ref: "master"}:  {Name: "camunda/optimize", Ref: "master", Color: "red"},
+			{Path: "camunda/web-modeler", Ref: "main"}: {Name: "camunda/web-modeler", Ref: "main", Color: "green"},
+		},
+	}
+	instance := New(&GitLabInstance{
+		Name:     "GitLab",
+		Url:      "https://gitlab.example.com",
+		Client:   client,
+		Projects: []GitLabProject{{Path: "camunda/optimize", Ref: "master"}, {Path: "camunda/web-modeler", Ref: "main"}},
+	})
+
+	claims, _ := NewClaimStore("")
+	claims.Claim("GitLab", "camunda/optimize@master", "jane", "")
+	instance.UseClaims(claims)
+
+	aggregation := instance.GetBrokenGitLabBuilds(Filter{})[0]
+
+	if len(aggregation.Jobs) != 1 || aggregation.Jobs[0].Name != "camunda/optimize" {
+		t.Fatalf("Only broken pipelines should be returned, got %+v", aggregation.Jobs)
+	}
+	if aggregation.Jobs[0].Claim == nil || aggregation.Type != "gitlab" || !aggregation.Health.IsOk() {
+		t.Fatalf("Claim should be attached to the broken pipeline, got %+v", aggregation)
+	}
+	if jobs := instance.Fetch()[0].Jobs; len(jobs) != 1 || !jobs[0].Broken || statusOf(jobs[0]) != StatusFailed {
+		t.Fatalf("Failed pipeline should be reported as broken, got %+v", jobs)
+	}
+}
+
+func TestDashboard_Fetch_GitLabPlaceholderKeepsDefaultRef(t *testing.T) {
+	project := GitLabProject{Path: "camunda/optimize"}
+	client := &TestGitLabClient{jobs: map[GitLabProject]GitLabJob{project: {Name: "camunda/optimize", Ref: "main", Color: "red"}}}
+	instance := New(&GitLabInstance{Name: "GitLab", Client: client, Projects: []GitLabProject{project}})
+
+	if jobs := instance.Fetch()[0].Jobs; len(jobs) != 1 || jobs[0].ID != "camunda/optimize@main" {
+		t.Fatalf("Pipeline should be identified by its default ref, got %+v", jobs)
+	}
+
+	client.errors = map[GitLabProject]error{project: errors.New("access denied")}
+	jobs := instance.Fetch()[0].Jobs
+
+	if len(jobs) != 1 || jobs[0].ID != "camunda/optimize@main" || !jobs[0].Unknown() {
+		t.Fatalf("Failing project should be identified by its last known default ref, got %+v", jobs)
+	}
+}
+
+func TestDashboard_Fetch_UnknownJobsAreNotBroken(t *testing.T) {
+	travisInstance := createDashboardInstanceWithSingleTravisInstance().providers[0].(*TravisInstance)
+	travisInstance.Client.(*TestTravisClient).error = errors.New("access denied")
+	gitHubInstance := &GitHubInstance{
+		Name:   "camunda",
+		Client: &TestGitHubClient{errors: map[string]error{"camunda/zeebe": errors.New("access denied")}},
+		Repos:  []GitHubRepository{{Owner: "camunda", Name: "zeebe"}},
+	}
+	gitLabInstance := &GitLabInstance{
+		Name:     "GitLab",
+		Client:   &TestGitLabClient{errors: map[GitLabProject]error{{Path: "camunda/optimize"}: errors.New("access denied")}},
+		Projects: []GitLabProject{{Path: "camunda/optimize"}},
+	}
+
+	for _, aggregation := range New(travisInstance, gitHubInstance, gitLabInstance).Fetch() {
+		if len(aggregation.Jobs) == 0 {
+			t.Fatalf("Failing requests of %s should be reported as jobs, got %+v", aggregation.Name, aggregation)
+		}
+		for _, job := range aggregation.Jobs {
+			if job.Broken || !job.Unknown() || statusOf(job) != StatusUnknown {
+				t.Errorf("Job of a failing request of %s should be unknown instead of broken, got %+v", aggregation.Name, job)
+			}
+		}
+	}
+}
+
 /**
  * Helpers
  */
@@ -104,6 +649,7 @@ func createDashboardInstanceWithSingleJenkinsInstance() *Dashboard {
 
 func createDashboardInstanceWithMocks(jenkinsInstances []*JenkinsInstance, travisInstances []*TravisInstance,
 	basicAuth bool, err error) *Dashboard {
+	var providers []Provider
 	for _, jenkinsInstance := range jenkinsInstances {
 		jenkinsClient := &TestJenkinsClient{
 			Name:          jenkinsInstance.Name,
@@ -117,11 +663,12 @@ func createDashboardInstanceWithMocks(jenkinsInstances []*JenkinsInstance, travi
 			queue:         &JenkinsQueue{},
 		}
 		jenkinsInstance.Client = jenkinsClient
+		providers = append(providers, jenkinsInstance)
 	}
 
 	for _, travisInstance := range travisInstances {
-		tj1 := TravisJob{Name: "repo1", URL: "https://github.com/org/repo1", Color: "red"}
-		tj2 := TravisJob{Name: "repo2", URL: "https://github.com/org/repo2", Color: "green"}
+		tj1 := TravisJob{Name: "repo1", Branch: "master", URL: "https://github.com/org/repo1", Color: "red"}
+		tj2 := TravisJob{Name: "repo2", Branch: "feature", URL: "https://github.com/org/repo2", Color: "green"}
 		r1 := TravisRepository{Organization: "org", Name: "repo1", Branch: "master"}
 		r2 := TravisRepository{Organization: "org", Name: "repo2", Branch: "feature"}
 
@@ -134,22 +681,20 @@ func createDashboardInstanceWithMocks(jenkinsInstances []*JenkinsInstance, travi
 		}
 		travisInstance.Client = travisClient
 		travisInstance.Repos = []TravisRepository{r1, r2}
+		providers = append(providers, travisInstance)
 	}
 
-	return &Dashboard{
-		jenkinsInstances: jenkinsInstances,
-		travisInstances:  travisInstances,
-	}
+	return New(providers...)
 }
 
 func createDashboardInstanceWithCustomJenkinsClient(jenkinsInstances []*JenkinsInstance, jenkinsClient *TestJenkinsClient) *Dashboard {
+	var providers []Provider
 	for _, jenkinsInstance := range jenkinsInstances {
 		jenkinsInstance.Client = jenkinsClient
+		providers = append(providers, jenkinsInstance)
 	}
 
-	return &Dashboard{
-		jenkinsInstances: jenkinsInstances,
-	}
+	return New(providers...)
 }
 
 /**
@@ -157,17 +702,97 @@ func createDashboardInstanceWithCustomJenkinsClient(jenkinsInstances []*JenkinsI
  */
 
 type TestTravisClient struct {
-	jobs  map[TravisRepository]TravisJob
-	error error
+	jobs         map[TravisRepository]TravisJob
+	branches     map[TravisRepository][]string
+	repositories []string
+	error        error
+	errors       map[TravisRepository]error
+	discoveries  int
 }
 
 func (t *TestTravisClient) Job(r TravisRepository) (TravisJob, error) {
 	if t.error != nil {
 		return TravisJob{}, t.error
 	}
+	if err, ok := t.errors[r]; ok {
+		return TravisJob{Name: r.Name, Branch: r.Branch, Color: "grey", Error: err.Error()}, err
+	}
 	return t.jobs[r], nil
 }
 
+func (t *TestTravisClient) Branches(r TravisRepository) ([]string, error) {
+	if err, ok := t.errors[r]; ok {
+		return nil, err
+	}
+	return t.branches[r], nil
+}
+
+func (t *TestTravisClient) Repositories(owner string) ([]string, error) {
+	t.discoveries++
+	if t.error != nil {
+		return nil, t.error
+	}
+	return t.repositories, nil
+}
+
+/**
+ * Test implementation of GitHubClient
+ */
+
+type TestGitHubClient struct {
+	jobs   map[string][]GitHubJob
+	errors map[string]error
+}
+
+func (t *TestGitHubClient) Jobs(r GitHubRepository) ([]GitHubJob, error) {
+	if err, ok := t.errors[r.Slug()]; ok {
+		return nil, err
+	}
+	return t.jobs[r.Slug()], nil
+}
+
+/**
+ * Test implementation of GitLabClient
+ */
+
+type TestGitLabClient struct {
+	jobs   map[GitLabProject]GitLabJob
+	errors map[GitLabProject]error
+}
+
+func (t *TestGitLabClient) Job(p GitLabProject) (GitLabJob, error) {
+	if err, ok := t.errors[p]; ok {
+		return GitLabJob{}, err
+	}
+	return t.jobs[p], nil
+}
+
+/**
+ * Implementations panicking on every call
+ */
+
+type PanickingTravisClient struct{}
+
+func (t *PanickingTravisClient) Job(r TravisRepository) (TravisJob, error) {
+	panic("travis client panicked")
+}
+
+func (t *PanickingTravisClient) Branches(r TravisRepository) ([]string, error) {
+	panic("travis client panicked")
+}
+
+func (t *PanickingTravisClient) Repositories(owner string) ([]string, error) {
+	panic("travis client panicked")
+}
+
+type PanickingJenkinsClient struct {
+	TestJenkinsClient
+}
+
+func (t *PanickingJenkinsClient) GetQueue() (*JenkinsQueue, error) {
+	panic("jenkins client panicked")
+}
+
 /**
  * Test implementation of JenkinsClient
  */
@@ -0,0 +1,453 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"log"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// maxDigestCategories is the number of failure categories listed in a digest.
+	maxDigestCategories = 5
+
+	digestTimeFormat = "2006-01-02 15:04 MST"
+
+	digestMarkdownTemplate = `# CI digest{{if .Name}} '{{.Name}}'{{end}}
+
+{{date .Since}} to {{date .Time}}
+
+## Broken jobs ({{len .Broken}})
+
+{{range .Broken}}- {{link .}} on {{.Instance}}{{if .Job.BrokenSince}}, broken since {{date .Job.BrokenSince}}{{end}}
+{{else}}No broken jobs.
+{{end}}
+## New breakages ({{len .New}})
+
+{{range .New}}- {{link .}} on {{.Instance}}, broke {{date .Time}}
+{{else}}No new breakages.
+{{end}}
+## Fixed jobs ({{len .Fixed}})
+
+{{range .Fixed}}- {{link .}} on {{.Instance}}, fixed {{date .Time}}
+{{else}}No fixed jobs.
+{{end}}
+{{- if .Categories}}
+## Top failure categories
+
+{{range .Categories}}- {{.Name}}: {{.Count}}
+{{end}}{{end}}
+{{- if .Unavailable}}
+## Unavailable instances
+
+{{range .Unavailable}}- {{.Name}} is {{.Health.State}}{{if .Health.Error}}: {{.Health.Error}}{{end}}
+{{end}}{{end}}`
+
+	digestHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CI digest{{if .Name}} '{{.Name}}'{{end}}</title>
+</head>
+<body>
+<h1>CI digest{{if .Name}} '{{.Name}}'{{end}}</h1>
+<p>{{date .Since}} to {{date .Time}}</p>
+<h2>Broken jobs ({{len .Broken}})</h2>
+{{if .Broken}}<ul>
+{{range .Broken}}<li>{{link .}} on {{.Instance}}{{if .Job.BrokenSince}}, broken since {{date .Job.BrokenSince}}{{end}}</li>
+{{end}}</ul>
+{{else}}<p>No broken jobs.</p>
+{{end}}<h2>New breakages ({{len .New}})</h2>
+{{if .New}}<ul>
+{{range .New}}<li>{{link .}} on {{.Instance}}, broke {{date .Time}}</li>
+{{end}}</ul>
+{{else}}<p>No new breakages.</p>
+{{end}}<h2>Fixed jobs ({{len .Fixed}})</h2>
+{{if .Fixed}}<ul>
+{{range .Fixed}}<li>{{link .}} on {{.Instance}}, fixed {{date .Time}}</li>
+{{end}}</ul>
+{{else}}<p>No fixed jobs.</p>
+{{end}}{{if .Categories}}<h2>Top failure categories</h2>
+<ul>
+{{range .Categories}}<li>{{.Name}}: {{.Count}}</li>
+{{end}}</ul>
+{{end}}{{if .Unavailable}}<h2>Unavailable instances</h2>
+<ul>
+{{range .Unavailable}}<li>{{.Name}} is {{.Health.State}}{{if .Health.Error}}: {{.Health.Error}}{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`
+)
+
+var (
+	digestMarkdown = template.Must(template.New("digest").Funcs(template.FuncMap{
+		"date": formatDigestTime,
+		"link": func(job DigestJob) string {
+			if job.Job.URL == "" {
+				return job.Title()
+			}
+			return fmt.Sprintf("[%s](%s)", job.Title(), job.Job.URL)
+		},
+	}).Parse(digestMarkdownTemplate))
+
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest").Funcs(htmltemplate.FuncMap{
+		"date": formatDigestTime,
+		"link": func(job DigestJob) htmltemplate.HTML {
+			title := htmltemplate.HTMLEscapeString(job.Title())
+			if job.Job.URL == "" {
+				return htmltemplate.HTML(title)
+			}
+			return htmltemplate.HTML(fmt.Sprintf(`<a href="%s">%s</a>`, htmltemplate.HTMLEscapeString(job.Job.URL), title))
+		},
+	}).Parse(digestHTMLTemplate))
+)
+
+// Digest summarizes the state of the jobs and their transitions since the previous digest.
+type Digest struct {
+	Name  string    `json:"name"`
+	Since time.Time `json:"since"`
+	Time  time.Time `json:"time"`
+	// Broken are the currently broken jobs, the longest broken first.
+	Broken []DigestJob `json:"broken"`
+	// New and Fixed are the jobs which broke respectively got fixed since the previous digest.
+	New   []DigestJob `json:"new"`
+	Fixed []DigestJob `json:"fixed"`
+	// Categories are the most frequent failure categories of the broken jobs.
+	Categories  []DigestCategory `json:"categories"`
+	Unavailable []Aggregation    `json:"unavailable,omitempty"`
+}
+
+// DigestJob is a job listed in a digest along with its instance and the time of its transition, if any.
+type DigestJob struct {
+	Instance string    `json:"instance"`
+	Job      Job       `json:"job"`
+	Time     time.Time `json:"time,omitempty"`
+}
+
+// Title returns the name of the job, or its ID if it has no name.
+func (j DigestJob) Title() string {
+	if j.Job.Name != "" {
+		return j.Job.Name
+	}
+	return j.Job.ID
+}
+
+// DigestCategory is a failure category along with the number of broken jobs it applies to.
+type DigestCategory struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DigestSchedule describes when a digest is created and where it's delivered to.
+type DigestSchedule struct {
+	Name     string
+	Schedule *CronSchedule
+	// Location is the time zone the schedule is evaluated in and the times of the digest are shown in.
+	Location *time.Location
+	// Instances are patterns in the syntax of path.Match, which match all instances if empty.
+	Instances []string
+	// Sinks receive the digest as Markdown message, their templates and filters don't apply.
+	Sinks []*Sink
+	// File is written with the latest digest, as HTML if it ends with '.html' or '.htm', as Markdown otherwise.
+	File string
+}
+
+// NewDigestSchedule creates the DigestSchedule with the given name from the config keys 'schedule', 'timezone',
+// 'instances', 'sinks' and 'file'. The sinks are referenced by their names.
+func NewDigestSchedule(name string, config NotifierConfig, sinks []*Sink) (*DigestSchedule, error) {
+	var cfg struct {
+		Schedule  string
+		Timezone  string
+		Instances []string
+		Sinks     []string
+		File      string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("Digest '%s' is misconfigured: %s", name, err)
+	}
+
+	schedule := &DigestSchedule{Name: name, Instances: cfg.Instances, File: cfg.File}
+	var err error
+	if schedule.Schedule, err = ParseCron(cfg.Schedule); err != nil {
+		return nil, fmt.Errorf("Digest '%s' has an invalid schedule: %s", name, err)
+	}
+	if schedule.Location, err = time.LoadLocation(cfg.Timezone); err != nil {
+		return nil, fmt.Errorf("Digest '%s' has an invalid timezone: %s", name, err)
+	}
+
+	for _, sinkName := range cfg.Sinks {
+		var found *Sink
+		for _, sink := range sinks {
+			if sink.Name == sinkName {
+				found = sink
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("Digest '%s' references the unknown sink '%s'.", name, sinkName)
+		}
+		schedule.Sinks = append(schedule.Sinks, found)
+	}
+	return schedule, nil
+}
+
+// Validate checks that the digest has a schedule, a destination and valid patterns.
+func (s *DigestSchedule) Validate() error {
+	if s.Schedule == nil {
+		return fmt.Errorf("Digest '%s' has no schedule.", s.Name)
+	}
+	if len(s.Sinks) == 0 && s.File == "" {
+		return fmt.Errorf("Digest '%s' has neither sinks nor a file.", s.Name)
+	}
+	for _, pattern := range s.Instances {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Digest '%s' has an invalid pattern '%s': %s", s.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// ScheduleDigest creates and delivers the digest according to its schedule until stop is closed. The first digest
+// covers the time since the call.
+func (d *Dashboard) ScheduleDigest(schedule *DigestSchedule, stop <-chan struct{}) {
+	location := schedule.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	last := time.Now().In(location)
+	for {
+		next := schedule.Schedule.Next(time.Now().In(location))
+		if next.IsZero() {
+			log.Printf("[WARN] Digest '%s' is never due with schedule '%s'.", schedule.Name, schedule.Schedule)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now().In(location)
+		if err := schedule.Deliver(d.Digest(schedule, last, now)); err != nil {
+			log.Printf("[WARN] %s", err)
+		}
+		last = now
+	}
+}
+
+// Digest summarizes the instances matched by the schedule: the currently broken jobs as well as the jobs which
+// broke or got fixed between since and now. Muted jobs are left out.
+func (d *Dashboard) Digest(schedule *DigestSchedule, since time.Time, now time.Time) *Digest {
+	location := schedule.Location
+	if location == nil {
+		location = time.Local
+	}
+	inLocation := func(job Job) Job {
+		if job.BrokenSince != nil {
+			brokenSince := job.BrokenSince.In(location)
+			job.BrokenSince = &brokenSince
+		}
+		return job
+	}
+	matches := func(instance string) bool {
+		return len(schedule.Instances) == 0 || matchesAny(schedule.Instances, instance)
+	}
+
+	digest := &Digest{
+		Name:       schedule.Name,
+		Since:      since.In(location),
+		Time:       now.In(location),
+		Broken:     make([]DigestJob, 0),
+		New:        make([]DigestJob, 0),
+		Fixed:      make([]DigestJob, 0),
+		Categories: make([]DigestCategory, 0),
+	}
+
+	counts := make(map[string]int)
+	for _, aggregation := range d.Fetch() {
+		if !matches(aggregation.Name) {
+			continue
+		}
+		if !aggregation.Health.IsAvailable() {
+			digest.Unavailable = append(digest.Unavailable, aggregation.Aggregation)
+		}
+		for _, job := range aggregation.Jobs {
+			if !job.Broken {
+				continue
+			}
+			digest.Broken = append(digest.Broken, DigestJob{Instance: aggregation.Name, Job: inLocation(job)})
+			for _, category := range failureCategories(job) {
+				counts[category]++
+			}
+		}
+	}
+	sort.SliceStable(digest.Broken, func(i, j int) bool {
+		a, b := digest.Broken[i].Job.BrokenSince, digest.Broken[j].Job.BrokenSince
+		return a != nil && (b == nil || a.Before(*b))
+	})
+
+	for name, count := range counts {
+		digest.Categories = append(digest.Categories, DigestCategory{Name: name, Count: count})
+	}
+	sort.Slice(digest.Categories, func(i, j int) bool {
+		a, b := digest.Categories[i], digest.Categories[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Name < b.Name
+	})
+	if len(digest.Categories) > maxDigestCategories {
+		digest.Categories = digest.Categories[:maxDigestCategories]
+	}
+
+	for _, event := range d.eventsSince(since) {
+		if event.Job == nil || event.Time.After(now) || !matches(event.Instance) {
+			continue
+		}
+		if d.mutes != nil && d.mutes.Find(event.Instance, event.Job.ID) != nil {
+			continue
+		}
+
+		job := DigestJob{Instance: event.Instance, Job: inLocation(*event.Job), Time: event.Time.In(location)}
+		switch event.Type {
+		case EventBroken:
+			digest.New = append(digest.New, job)
+		case EventFixed:
+			digest.Fixed = append(digest.Fixed, job)
+		}
+	}
+	return digest
+}
+
+// Deliver writes the digest to the file of the schedule and sends it to its sinks. Failed sinks don't keep the
+// digest from being delivered to the other ones, the errors are joined.
+func (s *DigestSchedule) Deliver(digest *Digest) error {
+	var errs []string
+	if s.File != "" {
+		if err := digest.WriteFile(s.File); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(s.Sinks) > 0 {
+		message, err := digest.Markdown()
+		if err != nil {
+			return err
+		}
+		event := Event{Type: EventDigest, Time: digest.Time, Digest: digest}
+		for _, sink := range s.Sinks {
+			if err := sink.Deliver(event, message); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Unable to deliver digest '%s': %s", s.Name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Markdown renders the digest as Markdown.
+func (g *Digest) Markdown() (string, error) {
+	var markdown bytes.Buffer
+	if err := digestMarkdown.Execute(&markdown, g); err != nil {
+		return "", fmt.Errorf("Unable to render digest '%s': %s", g.Name, err)
+	}
+	return markdown.String(), nil
+}
+
+// HTML renders the digest as HTML page.
+func (g *Digest) HTML() (string, error) {
+	var html bytes.Buffer
+	if err := digestHTML.Execute(&html, g); err != nil {
+		return "", fmt.Errorf("Unable to render digest '%s': %s", g.Name, err)
+	}
+	return html.String(), nil
+}
+
+// WriteFile writes the digest to the given file, as HTML if it ends with '.html' or '.htm', as Markdown otherwise.
+func (g *Digest) WriteFile(file string) error {
+	render := g.Markdown
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".html", ".htm":
+		render = g.HTML
+	}
+
+	content, err := render()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		return fmt.Errorf("Unable to write digest '%s': %s", g.Name, err)
+	}
+	return nil
+}
+
+// failureCategories returns the categories of the failure of a broken job. The failure causes found by the Jenkins
+// Build Failure Analyzer take precedence over failed tests, otherwise the category is derived from the color.
+func failureCategories(job Job) []string {
+	if jenkinsJob, ok := job.Details.(JenkinsJob); ok {
+		var categories []string
+		failedTests := false
+		for _, action := range jenkinsJob.LastBuild.Actions {
+			for _, cause := range action.FoundFailureCauses {
+				categories = append(categories, failureCauseCategories(cause)...)
+			}
+			failedTests = failedTests || action.FailCount > 0
+		}
+		if len(categories) > 0 {
+			return categories
+		}
+		if failedTests {
+			return []string{"Test failures"}
+		}
+	}
+
+	switch {
+	case job.Color == "grey" && job.Error != "":
+		return []string{"Unavailable"}
+	case strings.HasPrefix(job.Color, "yellow"), strings.HasPrefix(job.Color, "amber"):
+		return []string{"Unstable"}
+	case strings.HasPrefix(job.Color, "aborted"):
+		return []string{"Aborted"}
+	}
+	return []string{"Build failure"}
+}
+
+// failureCauseCategories returns the distinct categories of a failure cause found by the Build Failure Analyzer,
+// or 'Uncategorized' if it has none.
+func failureCauseCategories(cause interface{}) []string {
+	var categories []string
+	if attributes, ok := cause.(map[string]interface{}); ok {
+		values, _ := attributes["categories"].([]interface{})
+		for _, value := range values {
+			if category, ok := value.(string); ok && category != "" && !containsString(categories, category) {
+				categories = append(categories, category)
+			}
+		}
+	}
+	if len(categories) == 0 {
+		return []string{"Uncategorized"}
+	}
+	return categories
+}
+
+func formatDigestTime(t interface{}) string {
+	switch t := t.(type) {
+	case time.Time:
+		return t.Format(digestTimeFormat)
+	case *time.Time:
+		return t.Format(digestTimeFormat)
+	}
+	return ""
+}
@@ -0,0 +1,180 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboard_Digest(t *testing.T) {
+	release := &TestProvider{name: "Release", jobs: []Job{
+		{ID: "docs", Name: "docs", URL: "http://jenkins/job/docs/", Color: "red", Broken: true},
+		{ID: "website", Name: "website", Color: "red", Broken: true},
+	}}
+	other := &TestProvider{name: "Other", jobs: []Job{{ID: "build", Color: "red", Broken: true}}}
+	d := New(release, other)
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "Release", Job: "flaky-*"}})
+	d.UseMutes(mutes)
+	d.Fetch()
+	since := time.Now()
+
+	release.jobs = []Job{
+		{ID: "deploy", Name: "deploy", Color: "yellow", Broken: true},
+		{ID: "docs", Name: "docs", URL: "http://jenkins/job/docs/", Color: "red", Broken: true},
+		{ID: "flaky-it", Color: "red", Broken: true},
+	}
+	d.Fetch()
+
+	schedule := &DigestSchedule{Name: "daily", Location: time.UTC, Instances: []string{"Rel*"}}
+	digest := d.Digest(schedule, since, time.Now())
+
+	if len(digest.Broken) != 2 || digest.Broken[0].Job.ID != "docs" || digest.Broken[1].Job.ID != "deploy" {
+		t.Fatalf("Broken jobs should be listed longest broken first, got %+v", digest.Broken)
+	}
+	if len(digest.New) != 1 || digest.New[0].Job.ID != "deploy" || digest.New[0].Instance != "Release" {
+		t.Fatalf("New breakages should be listed without muted jobs, got %+v", digest.New)
+	}
+	if len(digest.Fixed) != 1 || digest.Fixed[0].Job.ID != "website" {
+		t.Fatalf("Fixed jobs should be listed, got %+v", digest.Fixed)
+	}
+	if len(digest.Categories) != 2 || digest.Categories[0] != (DigestCategory{"Build failure", 1}) ||
+		digest.Categories[1] != (DigestCategory{"Unstable", 1}) {
+		t.Fatalf("Wrong failure categories, got %+v", digest.Categories)
+	}
+	if digest.Broken[0].Job.BrokenSince.Location() != time.UTC {
+		t.Fatalf("Times should be in the location of the schedule, got %s", digest.Broken[0].Job.BrokenSince)
+	}
+
+	if later := d.Digest(schedule, time.Now(), time.Now()); len(later.New) != 0 || len(later.Fixed) != 0 {
+		t.Fatalf("Transitions before the previous digest shouldn't be listed, got %+v", later)
+	}
+}
+
+func TestDigest_Render(t *testing.T) {
+	brokenSince := time.Date(2018, time.May, 14, 8, 0, 0, 0, time.UTC)
+	digest := &Digest{
+		Name:  "daily",
+		Since: time.Date(2018, time.May, 15, 8, 0, 0, 0, time.UTC),
+		Time:  time.Date(2018, time.May, 16, 8, 0, 0, 0, time.UTC),
+		Broken: []DigestJob{
+			{Instance: "Release", Job: Job{ID: "docs", Name: "<docs>", URL: "http://jenkins/job/docs/", BrokenSince: &brokenSince}},
+		},
+		Fixed:       []DigestJob{{Instance: "Release", Job: Job{ID: "website"}, Time: time.Date(2018, time.May, 15, 9, 30, 0, 0, time.UTC)}},
+		Categories:  []DigestCategory{{"Test failures", 3}},
+		Unavailable: []Aggregation{{Name: "camunda", Health: Health{State: HealthDown, Error: "timeout"}}},
+	}
+
+	markdown, err := digest.Markdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"# CI digest 'daily'",
+		"2018-05-15 08:00 UTC to 2018-05-16 08:00 UTC",
+		"- [<docs>](http://jenkins/job/docs/) on Release, broken since 2018-05-14 08:00 UTC\n",
+		"No new breakages.",
+		"- website on Release, fixed 2018-05-15 09:30 UTC\n",
+		"- Test failures: 3\n",
+		"- camunda is down: timeout\n",
+	} {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("Markdown should contain '%s', got:\n%s", expected, markdown)
+		}
+	}
+
+	html, err := digest.HTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<li><a href="http://jenkins/job/docs/">&lt;docs&gt;</a> on Release, broken since 2018-05-14 08:00 UTC</li>`,
+		"<p>No new breakages.</p>",
+		"<li>Test failures: 3</li>",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("HTML should contain '%s', got:\n%s", expected, html)
+		}
+	}
+}
+
+func TestDigestSchedule_Deliver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "digest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	notifier := &TestNotifier{failures: 1}
+	sink := &Sink{Name: "Alerts", Notifier: notifier, Template: mustMessageTemplate(t), Events: []string{EventBroken}, Retries: 1}
+	schedule, err := NewDigestSchedule("daily", NotifierConfig{
+		"schedule": "0 8 * * mon-fri",
+		"timezone": "Europe/Berlin",
+		"sinks":    []interface{}{"Alerts"},
+		"file":     filepath.Join(dir, "digest.html"),
+	}, []*Sink{sink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schedule.Deliver(&Digest{Name: "daily"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.messages) != 1 || !strings.HasPrefix(notifier.messages[0], "# CI digest 'daily'") {
+		t.Fatalf("Digest should be sent as Markdown regardless of the sink filters, got %v", notifier.messages)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(dir, "digest.html"))
+	if err != nil || !strings.HasPrefix(string(content), "<!DOCTYPE html>") {
+		t.Fatalf("Digest should be written as HTML, got %s %v", content, err)
+	}
+}
+
+func TestNewDigestSchedule_Invalid(t *testing.T) {
+	configs := []NotifierConfig{
+		{"schedule": "0 25 * * *", "file": "digest.md"},
+		{"schedule": "@daily", "timezone": "Mars/Olympus", "file": "digest.md"},
+		{"schedule": "@daily", "sinks": []interface{}{"Missing"}},
+	}
+	for i, config := range configs {
+		if _, err := NewDigestSchedule("daily", config, nil); err == nil {
+			t.Errorf("Digest config #%d should be invalid", i)
+		}
+	}
+
+	schedule, _ := NewDigestSchedule("daily", NotifierConfig{"schedule": "@daily"}, nil)
+	if err := schedule.Validate(); err == nil {
+		t.Error("Digest without sinks and file should be invalid")
+	}
+}
+
+func TestFailureCategories(t *testing.T) {
+	analyzed := JenkinsJob{}
+	analyzed.LastBuild.Actions = []JenkinsAction{{FailCount: 2, FoundFailureCauses: []interface{}{
+		map[string]interface{}{"categories": []interface{}{"Infrastructure", "Network"}},
+		map[string]interface{}{"description": "Something went wrong"},
+	}}}
+	tested := JenkinsJob{}
+	tested.LastBuild.Actions = []JenkinsAction{{FailCount: 2}}
+
+	tests := []struct {
+		job        Job
+		categories string
+	}{
+		{Job{Color: "red", Details: analyzed}, "Infrastructure,Network,Uncategorized"},
+		{Job{Color: "yellow", Details: tested}, "Test failures"},
+		{Job{Color: "red_anime"}, "Build failure"},
+		{Job{Color: "amber"}, "Unstable"},
+		{Job{Color: "aborted"}, "Aborted"},
+		{Job{Color: "grey", Error: "not found"}, "Unavailable"},
+	}
+	for i, test := range tests {
+		if categories := strings.Join(failureCategories(test.job), ","); categories != test.categories {
+			t.Errorf("Wrong categories of job #%d. Expected: %s, got: %s", i, test.categories, categories)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultExecTimeout is the time after which a command is killed, if no timeout is configured.
+	DefaultExecTimeout = 30 * time.Second
+
+	// maxExecStderr is the number of bytes of stderr which are included in the error of a failed command.
+	maxExecStderr = 1024
+)
+
+// ExecInstance runs a command for custom checks, e.g. a shell script checking for a nightly artifact.
+// The command prints the status of its jobs as JSON to stdout, which is mapped onto the jobs by its JSONMapping.
+// A command which exits with a non-zero status or times out marks the instance as down.
+type ExecInstance struct {
+	JSONMapping
+	Name string
+	// Command is the executable along with its arguments.
+	Command []string
+	// Dir is the working directory of the command, defaults to the one of the dashboard.
+	Dir string
+	// Env holds additional environment variables in the form 'key=value'.
+	Env     []string
+	Timeout time.Duration
+}
+
+func init() {
+	RegisterProvider("exec", newExecProvider)
+}
+
+// newExecProvider creates an ExecInstance from the config keys 'command', 'dir', 'env' and 'timeout', along with the
+// ones of its JSONMapping. The command is either a list of the executable and its arguments, or a string which is
+// run by 'sh -c'.
+func newExecProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		jsonMappingConfig
+		Command interface{}
+		Dir     string
+		Env     map[string]string
+		Timeout string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	mapping, err := newJSONMapping(cfg.jsonMappingConfig)
+	if err != nil {
+		return nil, err
+	}
+	instance := &ExecInstance{JSONMapping: mapping, Name: name, Dir: cfg.Dir, Timeout: DefaultExecTimeout}
+
+	switch command := cfg.Command.(type) {
+	case string:
+		instance.Command = []string{"sh", "-c", command}
+	case []interface{}:
+		for _, arg := range command {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("Argument '%v' of the command is not a string", arg)
+			}
+			instance.Command = append(instance.Command, s)
+		}
+	}
+
+	for key, value := range cfg.Env {
+		instance.Env = append(instance.Env, key+"="+value)
+	}
+
+	if cfg.Timeout != "" {
+		if instance.Timeout, err = time.ParseDuration(cfg.Timeout); err != nil {
+			return nil, fmt.Errorf("Invalid timeout: %s", err)
+		}
+	}
+
+	return instance, nil
+}
+
+func (e *ExecInstance) Info() Aggregation {
+	return Aggregation{Type: "exec", Name: e.Name}
+}
+
+// Validate checks that the instance has a command and a positive timeout.
+func (e *ExecInstance) Validate() error {
+	if e.Name == "" {
+		return fmt.Errorf("Exec instance has no name.")
+	}
+	if len(e.Command) == 0 || e.Command[0] == "" {
+		return fmt.Errorf("Exec instance '%s' has no command.", e.Name)
+	}
+	if e.Timeout <= 0 {
+		return fmt.Errorf("Exec instance '%s' has no positive timeout.", e.Name)
+	}
+	if err := e.JSONMapping.validate(); err != nil {
+		return fmt.Errorf("Exec instance '%s' is misconfigured: %s", e.Name, err)
+	}
+	return nil
+}
+
+// Fetch runs the command and returns the jobs of its output which aren't successful.
+func (e *ExecInstance) Fetch() *InstanceAggregation {
+	start := time.Now()
+	aggregation := &InstanceAggregation{Aggregation: e.Info(), Jobs: make([]Job, 0)}
+
+	var output interface{}
+	err := e.run(&output)
+	if err != nil {
+		log.Printf("[WARN] %s (command): %s", e.Name, err)
+	}
+	aggregation.Health = newHealth([]string{"command"}, []error{err}, time.Since(start))
+	if err != nil {
+		return aggregation
+	}
+
+	aggregation.Jobs = e.brokenJobs(output)
+	return aggregation
+}
+
+// run runs the command and decodes its stdout into v. Errors of the command include its stderr.
+func (e *ExecInstance) run(v interface{}) error {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+	cmd.Dir = e.Dir
+	cmd.Env = append(os.Environ(), e.Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("Unable to run command: %s", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+		if err != nil {
+			err = fmt.Errorf("Command failed with %s", err)
+		}
+	case <-time.After(e.Timeout):
+		// the whole process group is killed, so children of a shell don't keep stdout open
+		killProcessGroup(cmd)
+		<-done
+		err = fmt.Errorf("Command timed out after %s", e.Timeout)
+	}
+	if err == nil {
+		if err = json.Unmarshal(stdout.Bytes(), v); err != nil {
+			err = fmt.Errorf("Command returned invalid JSON: %s", err)
+		}
+	}
+
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%s: %s", err, truncate(strings.TrimSpace(stderr.String()), maxExecStderr))
+	}
+	return err
+}
+
+// truncate shortens the given string to at most max bytes, marking the cut with '...'.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "..."
+}
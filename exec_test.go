@@ -0,0 +1,110 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecInstance_Fetch(t *testing.T) {
+	instance := newExecInstance(t, ProviderConfig{
+		"command": `echo '{"artifacts": [{"name": "nightly", "status": "missing"}, {"name": "weekly", "status": "ok"}]}'`,
+		"jobs":    "$.artifacts",
+		"colors":  map[string]interface{}{"missing": "red"},
+	})
+
+	aggregation := instance.Fetch()
+
+	expected := []Job{
+		{ID: "nightly", Name: "nightly", Color: "red", Broken: true, Details: JSONJob{Name: "nightly", Status: "missing"}},
+	}
+	if !aggregation.Health.IsOk() || !reflect.DeepEqual(aggregation.Jobs, expected) {
+		t.Fatalf("Wrong jobs returned. Expected: %+v, got: %+v", expected, aggregation)
+	}
+}
+
+func TestExecInstance_FetchWithArguments(t *testing.T) {
+	instance := newExecInstance(t, ProviderConfig{
+		"command": []interface{}{"sh", "-c", `echo "[{\"name\": \"$1\", \"status\": \"$CHECK_STATUS\"}]"`, "check", "nightly"},
+		"env":     map[string]interface{}{"CHECK_STATUS": "failed"},
+	})
+
+	aggregation := instance.Fetch()
+
+	if len(aggregation.Jobs) != 1 || aggregation.Jobs[0].ID != "nightly" || aggregation.Jobs[0].Color != "red" {
+		t.Fatalf("Arguments and environment should be passed to the command, got %+v", aggregation)
+	}
+}
+
+func TestExecInstance_FetchFailingCommand(t *testing.T) {
+	instance := newExecInstance(t, ProviderConfig{"command": "echo 'artifact store not reachable' >&2; exit 3"})
+
+	health := instance.Fetch().Health
+
+	if health.State != HealthDown || health.FailedRequest != "command" {
+		t.Fatalf("Instance should be down, got %+v", health)
+	}
+	if !strings.Contains(health.Error, "exit status 3") || !strings.Contains(health.Error, "artifact store not reachable") {
+		t.Fatalf("Error should contain the exit status and stderr, got '%s'", health.Error)
+	}
+}
+
+func TestExecInstance_FetchTimeout(t *testing.T) {
+	instance := newExecInstance(t, ProviderConfig{"command": "sleep 10; echo '[]'", "timeout": "100ms"})
+
+	start := time.Now()
+	health := instance.Fetch().Health
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("Command should be killed after the timeout, took %s", time.Since(start))
+	}
+	if health.State != HealthDown || !strings.Contains(health.Error, "timed out after 100ms") {
+		t.Fatalf("Instance should be down after a timeout, got %+v", health)
+	}
+}
+
+func TestExecInstance_FetchInvalidOutput(t *testing.T) {
+	instance := newExecInstance(t, ProviderConfig{"command": "echo 'nightly: ok'"})
+
+	health := instance.Fetch().Health
+
+	if health.State != HealthDown || !strings.Contains(health.Error, "invalid JSON") {
+		t.Fatalf("Instance should be down for invalid output, got %+v", health)
+	}
+}
+
+func TestExecInstance_Validate(t *testing.T) {
+	provider, err := NewProvider("exec", "Checks", ProviderConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Validate(); err == nil || !strings.Contains(err.Error(), "no command") {
+		t.Fatalf("Instance without command should be rejected, got %v", err)
+	}
+
+	_, err = NewProvider("exec", "Checks", ProviderConfig{"command": "true", "timeout": "soon"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid timeout") {
+		t.Fatalf("Invalid timeout should be rejected, got %v", err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if s := truncate("artifact missing", 8); s != "artifact..." {
+		t.Fatalf("String should be truncated, got '%s'", s)
+	}
+	if s := truncate("missing", 8); s != "missing" {
+		t.Fatalf("Short string should be kept, got '%s'", s)
+	}
+}
+
+func newExecInstance(t *testing.T, config ProviderConfig) Provider {
+	provider, err := NewProvider("exec", "Checks", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
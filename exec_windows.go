@@ -0,0 +1,15 @@
+//go:build windows
+// +build windows
+
+package dashboard
+
+import "os/exec"
+
+// setProcessGroup does nothing on Windows, where commands are killed without their children.
+func setProcessGroup(cmd *exec.Cmd) {
+}
+
+// killProcessGroup kills the command.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
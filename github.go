@@ -0,0 +1,456 @@
+package dashboard
+
+import (
+	"fmt"
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const (
+	// GitHubApiUrl is the default API of github.com.
+	GitHubApiUrl = "https://api.github.com/"
+	// GitHubWebUrl is the default web interface of github.com.
+	GitHubWebUrl = "https://github.com/"
+
+	gitHubRunsPerPage = 100
+)
+
+// GitHubInstance holds the repositories of a GitHub owner whose GitHub Actions workflows are watched.
+type GitHubInstance struct {
+	Name string
+	// WebUrl is the web interface of the GitHub installation, defaults to GitHubWebUrl.
+	WebUrl string
+	Repos  []GitHubRepository
+	// WebhookSecret verifies the signature of the webhooks sent by GitHub, webhooks are rejected without it.
+	WebhookSecret string
+	Client        GitHub
+}
+
+func init() {
+	RegisterProvider("github", newGitHubProvider)
+}
+
+// newGitHubProvider creates a GitHubInstance for the owner with the given name from the config keys 'apiUrl',
+// 'webUrl', 'accessToken', 'webhookSecret' and 'repos'. Repositories without 'branches' are watched on their
+// default branch.
+func newGitHubProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		ApiUrl        string
+		WebUrl        string
+		AccessToken   string
+		WebhookSecret string
+		Repos         []struct {
+			Name      string
+			Workflows []string
+			Branches  []string
+		}
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	instance := &GitHubInstance{
+		Name:          name,
+		WebUrl:        cfg.WebUrl,
+		WebhookSecret: cfg.WebhookSecret,
+		Client:        NewGitHubClient(cfg.ApiUrl, cfg.AccessToken),
+	}
+	for _, r := range cfg.Repos {
+		if r.Name == "" {
+			continue
+		}
+
+		// an empty branch stands for the default branch of the repository
+		branches := r.Branches
+		if len(branches) == 0 {
+			branches = []string{""}
+		}
+
+		for _, branch := range branches {
+			instance.Repos = append(instance.Repos, GitHubRepository{Owner: name, Name: r.Name, Branch: branch, Workflows: r.Workflows})
+		}
+	}
+
+	return instance, nil
+}
+
+func (g *GitHubInstance) Info() Aggregation {
+	return Aggregation{Type: "github", Name: g.Name, Url: g.Url()}
+}
+
+// Fetch retrieves the broken workflows of the watched repositories.
+func (g *GitHubInstance) Fetch() *InstanceAggregation {
+	gitHubAggregation := getBrokenBuildsForGitHubInstance(g)
+	aggregation := &InstanceAggregation{
+		Aggregation: gitHubAggregation.Aggregation,
+		Jobs:        make([]Job, 0, len(gitHubAggregation.Jobs)),
+	}
+
+	for _, job := range gitHubAggregation.Jobs {
+		aggregation.Jobs = append(aggregation.Jobs, gitHubJobOf(job))
+	}
+	return aggregation
+}
+
+// gitHubJobOf converts a workflow into a Job.
+func gitHubJobOf(job GitHubJob) Job {
+	return Job{
+		ID:      job.ID(),
+		Name:    displayName(job.Name, job.Workflow, job.Branch),
+		URL:     job.URL,
+		Color:   job.Color,
+		Running: job.Running,
+		Broken:  job.Error == "" && !job.IsSuccessful(),
+		Error:   job.Error,
+		Details: job,
+	}
+}
+
+func (g *GitHubInstance) Url() string {
+	return gitHubWebUrl(g.WebUrl) + g.Name
+}
+
+// Validate checks that the instance is named and has a client to access GitHub.
+func (g *GitHubInstance) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("GitHub owner has no name.")
+	}
+	if g.Client == nil {
+		return fmt.Errorf("GitHub owner '%s' has no client.", g.Name)
+	}
+	if g.WebUrl != "" {
+		if err := validateUrl(g.WebUrl); err != nil {
+			return fmt.Errorf("GitHub owner '%s' has an invalid web url: %s", g.Name, err)
+		}
+	}
+	for _, r := range g.Repos {
+		if r.Name == "" {
+			return fmt.Errorf("GitHub owner '%s' has a repository without name.", g.Name)
+		}
+	}
+	return nil
+}
+
+// Holds all dashboard relevant informations for a GitHub owner
+type GitHubAggregation struct {
+	Aggregation
+	Jobs  []GitHubJob `json:"jobs"`
+	Muted []GitHubJob `json:"muted"`
+}
+
+// gitHubAggregationOf converts the aggregation of an instance back into the GitHubAggregation served by the GitHub endpoint.
+func gitHubAggregationOf(aggregation *InstanceAggregation) *GitHubAggregation {
+	return &GitHubAggregation{
+		Aggregation: aggregation.Aggregation,
+		Jobs:        gitHubJobsOf(aggregation.Jobs),
+		Muted:       gitHubJobsOf(aggregation.Muted),
+	}
+}
+
+func gitHubJobsOf(jobs []Job) []GitHubJob {
+	gitHubJobs := make([]GitHubJob, 0, len(jobs))
+	for _, job := range jobs {
+		if gitHubJob, ok := job.Details.(GitHubJob); ok {
+			gitHubJob.Claim = job.Claim
+			gitHubJob.Mute = job.Mute
+			gitHubJob.Owner = job.Owner
+			gitHubJobs = append(gitHubJobs, gitHubJob)
+		}
+	}
+	return gitHubJobs
+}
+
+// GitHub is the high-level API for accessing the GitHub Actions of a repository.
+type GitHub interface {
+	// Jobs returns the latest run of every watched workflow of the repository on its branch.
+	Jobs(r GitHubRepository) ([]GitHubJob, error)
+}
+
+// GitHubRepository is a branch of a repository whose workflows are watched.
+// An empty branch stands for the default branch of the repository, no workflows for all of them.
+type GitHubRepository struct {
+	Owner     string
+	Name      string
+	Branch    string
+	Workflows []string
+}
+
+// Slug returns the 'owner/name' identifier of the repository.
+func (r GitHubRepository) Slug() string {
+	return r.Owner + "/" + r.Name
+}
+
+// watches returns true, if the workflow with the given name and file path is watched.
+// Workflows can be configured by their name or the name of their file, e.g. 'ci.yml'.
+func (r GitHubRepository) watches(name string, file string) bool {
+	if len(r.Workflows) == 0 {
+		return true
+	}
+	for _, workflow := range r.Workflows {
+		if workflow == name || workflow == path.Base(file) {
+			return true
+		}
+	}
+	return false
+}
+
+type GitHubJob struct {
+	Name     string `json:"name"`
+	Workflow string `json:"workflow"`
+	Branch   string `json:"branch"`
+	// URL points at the latest run of the workflow.
+	URL       string        `json:"url"`
+	Color     string        `json:"color"`
+	RunID     int64         `json:"runId,omitempty"`
+	RunNumber int           `json:"number,omitempty"`
+	State     string        `json:"state,omitempty"`
+	Commit    *GitHubCommit `json:"commit,omitempty"`
+	// Running is true, if the latest run hasn't finished yet. The color is the one of the last completed run then.
+	Running       bool             `json:"running"`
+	LastCompleted *GitHubRunResult `json:"lastCompleted,omitempty"`
+	// FailedJobs are the names of the jobs of the last completed run which failed.
+	FailedJobs []GitHubFailedJob `json:"failedJobs,omitempty"`
+	// Error describes why the state of the workflow couldn't be retrieved.
+	Error string `json:"error,omitempty"`
+	Claim *Claim `json:"claim,omitempty"`
+	Mute  *Mute  `json:"mute,omitempty"`
+	Owner *Team  `json:"owner,omitempty"`
+}
+
+// GitHubCommit is the head commit of a workflow run.
+type GitHubCommit struct {
+	Sha     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author,omitempty"`
+}
+
+// GitHubRunResult is the outcome of a completed workflow run.
+type GitHubRunResult struct {
+	RunID     int64  `json:"runId"`
+	RunNumber int    `json:"number"`
+	State     string `json:"state"`
+	URL       string `json:"url"`
+}
+
+// GitHubFailedJob is a failed job of a workflow run.
+type GitHubFailedJob struct {
+	Name       string `json:"name"`
+	Conclusion string `json:"conclusion"`
+	URL        string `json:"url"`
+}
+
+func (j GitHubJob) IsSuccessful() bool {
+	return j.Color == "green" || j.Color == ""
+}
+
+// ID returns the name which identifies the job inside its GitHub instance, i.e. 'repository/workflow@branch'. The
+// placeholder of a repository whose workflows couldn't be fetched is identified by the repository as configured, i.e.
+// 'repository' or 'repository@branch', as neither its workflows nor its default branch may be known.
+func (j GitHubJob) ID() string {
+	if j.Workflow == "" {
+		return strings.TrimSuffix(j.Name+"@"+j.Branch, "@")
+	}
+	return j.Name + "/" + j.Workflow + "@" + j.Branch
+}
+
+// GitHubClient implements the GitHub interface using the REST API of GitHub.
+type GitHubClient struct {
+	client *client.HTTPClient
+}
+
+type gitHubRepositoryResponse struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+type gitHubWorkflowRunsResponse struct {
+	WorkflowRuns []gitHubWorkflowRun `json:"workflow_runs"`
+}
+
+type gitHubWorkflowRun struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	WorkflowID int64  `json:"workflow_id"`
+	HeadBranch string `json:"head_branch"`
+	HeadSha    string `json:"head_sha"`
+	RunNumber  int    `json:"run_number"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HtmlUrl    string `json:"html_url"`
+	HeadCommit *struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"head_commit"`
+}
+
+type gitHubWorkflowJobsResponse struct {
+	Jobs []struct {
+		Name       string `json:"name"`
+		Conclusion string `json:"conclusion"`
+		HtmlUrl    string `json:"html_url"`
+	} `json:"jobs"`
+}
+
+// Jobs returns the latest run of every watched workflow of the repository on its branch. Only the latest
+// runs of the branch are taken into account, so workflows which didn't run for a long time may be missing.
+func (c *GitHubClient) Jobs(r GitHubRepository) ([]GitHubJob, error) {
+	branch := r.Branch
+	if branch == "" {
+		repository := &gitHubRepositoryResponse{}
+		if err := c.get("repos/"+r.Slug(), repository, "GitHubRepository"); err != nil {
+			return nil, err
+		}
+		branch = repository.DefaultBranch
+	}
+
+	runs := &gitHubWorkflowRunsResponse{}
+	runsPath := fmt.Sprintf("repos/%s/actions/runs?branch=%s&per_page=%d", r.Slug(), url.QueryEscape(branch), gitHubRunsPerPage)
+	if err := c.get(runsPath, runs, "GitHubWorkflowRuns"); err != nil {
+		return nil, err
+	}
+
+	// the runs are ordered from newest to oldest, so the first run of every workflow is its latest one
+	var workflows []int64
+	runsByWorkflow := make(map[int64][]gitHubWorkflowRun)
+	for _, run := range runs.WorkflowRuns {
+		if run.Event == "pull_request" || !r.watches(run.Name, run.Path) {
+			continue
+		}
+		if _, ok := runsByWorkflow[run.WorkflowID]; !ok {
+			workflows = append(workflows, run.WorkflowID)
+		}
+		runsByWorkflow[run.WorkflowID] = append(runsByWorkflow[run.WorkflowID], run)
+	}
+
+	var firstErr error
+	jobs := make([]GitHubJob, 0, len(workflows))
+	for _, workflow := range workflows {
+		job, err := c.job(r, branch, runsByWorkflow[workflow])
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, firstErr
+}
+
+// job converts the runs of a single workflow, ordered from newest to oldest, into a GitHubJob.
+func (c *GitHubClient) job(r GitHubRepository, branch string, runs []gitHubWorkflowRun) (GitHubJob, error) {
+	latest := runs[0]
+	job := GitHubJob{
+		Name:      r.Name,
+		Workflow:  latest.Name,
+		Branch:    branch,
+		URL:       latest.HtmlUrl,
+		RunID:     latest.ID,
+		RunNumber: latest.RunNumber,
+		State:     latest.Status,
+	}
+	if latest.Status == "completed" {
+		job.State = latest.Conclusion
+	}
+	if latest.HeadCommit != nil {
+		job.Commit = &GitHubCommit{Sha: latest.HeadSha, Message: latest.HeadCommit.Message, Author: latest.HeadCommit.Author.Name}
+	}
+
+	// a running workflow doesn't have a result yet, so the job keeps the result of the last completed run
+	completed := &latest
+	if latest.Status != "completed" {
+		job.Running = true
+		completed = nil
+		for i := range runs {
+			if runs[i].Status == "completed" {
+				completed = &runs[i]
+				break
+			}
+		}
+		if completed == nil {
+			return job, nil
+		}
+		job.LastCompleted = &GitHubRunResult{
+			RunID:     completed.ID,
+			RunNumber: completed.RunNumber,
+			State:     completed.Conclusion,
+			URL:       completed.HtmlUrl,
+		}
+	}
+
+	job.Color = gitHubColor(completed.Conclusion)
+	if job.Color != "red" {
+		return job, nil
+	}
+
+	failedJobs, err := c.failedJobs(r, completed.ID)
+	if err != nil {
+		job.Error = fmt.Sprintf("Unable to retrieve the failed jobs of run #%d: %s", completed.RunNumber, err)
+		return job, err
+	}
+	job.FailedJobs = failedJobs
+	return job, nil
+}
+
+func (c *GitHubClient) failedJobs(r GitHubRepository, runID int64) ([]GitHubFailedJob, error) {
+	jobs := &gitHubWorkflowJobsResponse{}
+	if err := c.get(fmt.Sprintf("repos/%s/actions/runs/%d/jobs?per_page=100", r.Slug(), runID), jobs, "GitHubWorkflowJobs"); err != nil {
+		return nil, err
+	}
+
+	failed := make([]GitHubFailedJob, 0)
+	for _, job := range jobs.Jobs {
+		if gitHubColor(job.Conclusion) == "red" {
+			failed = append(failed, GitHubFailedJob{Name: job.Name, Conclusion: job.Conclusion, URL: job.HtmlUrl})
+		}
+	}
+	return failed, nil
+}
+
+func (c *GitHubClient) get(path string, v interface{}, component string) error {
+	response, err := c.client.GetFrom(path)
+	if err != nil {
+		return err
+	}
+	return processResponse(response, v, component)
+}
+
+// gitHubColor maps the conclusion of a completed workflow run or job onto the color of the job.
+func gitHubColor(conclusion string) string {
+	switch conclusion {
+	case "success", "neutral", "skipped":
+		return "green"
+	case "failure", "timed_out", "startup_failure":
+		return "red"
+	case "cancelled":
+		return "aborted"
+	}
+	return "grey"
+}
+
+// gitHubWebUrl returns the given web url with a trailing slash, or the default web url if it is empty.
+func gitHubWebUrl(webUrl string) string {
+	if webUrl == "" {
+		return GitHubWebUrl
+	}
+	return strings.TrimSuffix(webUrl, "/") + "/"
+}
+
+// NewGitHubClient returns a client for the GitHub API at apiUrl, which authenticates with the given token.
+// An empty url defaults to github.com, an empty token accesses the API anonymously.
+func NewGitHubClient(apiUrl string, token string) GitHub {
+	if apiUrl == "" {
+		apiUrl = GitHubApiUrl
+	}
+
+	config := client.NewHTTPConfig(apiUrl, "", "", "application/json").
+		SetHeader("Accept", "application/vnd.github.v3+json")
+	if token != "" {
+		config.SetHeader("Authorization", "token "+token)
+	}
+
+	return &GitHubClient{client: client.NewHTTPClient(config)}
+}
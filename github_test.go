@@ -0,0 +1,154 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGitHubClient_Jobs(t *testing.T) {
+	server := mockGitHubServer(t)
+	defer server.Close()
+
+	gc := NewGitHubClient(server.URL, "secret")
+	jobs, err := gc.Jobs(GitHubRepository{Owner: "camunda", Name: "zeebe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []GitHubJob{
+		{
+			Name:      "zeebe",
+			Workflow:  "CI",
+			Branch:    "main",
+			URL:       "https://github.com/camunda/zeebe/actions/runs/1003",
+			Color:     "red",
+			RunID:     1003,
+			RunNumber: 103,
+			State:     "in_progress",
+			Commit: &GitHubCommit{
+				Sha:     "9f3a0e2c6d1b4a5f8e7c0b2d3a4f5e6d7c8b9a01",
+				Message: "fix(broker): close the log stream on shutdown",
+				Author:  "Jane Doe",
+			},
+			Running: true,
+			LastCompleted: &GitHubRunResult{
+				RunID:     1002,
+				RunNumber: 102,
+				State:     "failure",
+				URL:       "https://github.com/camunda/zeebe/actions/runs/1002",
+			},
+			FailedJobs: []GitHubFailedJob{
+				{Name: "test (ubuntu-latest)", Conclusion: "failure", URL: "https://github.com/camunda/zeebe/runs/50002"},
+				{Name: "test (windows-latest)", Conclusion: "timed_out", URL: "https://github.com/camunda/zeebe/runs/50003"},
+			},
+		},
+		{
+			Name:      "zeebe",
+			Workflow:  "Docs",
+			Branch:    "main",
+			URL:       "https://github.com/camunda/zeebe/actions/runs/2001",
+			Color:     "green",
+			RunID:     2001,
+			RunNumber: 55,
+			State:     "success",
+			Commit: &GitHubCommit{
+				Sha:     "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b",
+				Message: "docs: update the getting started guide",
+				Author:  "John Doe",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(jobs, expected) {
+		t.Fatalf("Wrong GitHubJobs returned. Expected: %+v, got: %+v", expected, jobs)
+	}
+}
+
+func TestGitHubClient_JobsOfWorkflows(t *testing.T) {
+	server := mockGitHubServer(t)
+	defer server.Close()
+
+	gc := NewGitHubClient(server.URL, "secret")
+	jobs, err := gc.Jobs(GitHubRepository{Owner: "camunda", Name: "zeebe", Branch: "main", Workflows: []string{"docs.yml"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 1 || jobs[0].Workflow != "Docs" || jobs[0].ID() != "zeebe/Docs@main" {
+		t.Fatalf("Only the configured workflow should be returned, got %+v", jobs)
+	}
+}
+
+func TestGitHubClient_Unauthorized(t *testing.T) {
+	server := mockGitHubServer(t)
+	defer server.Close()
+
+	gc := NewGitHubClient(server.URL, "revoked")
+	_, err := gc.Jobs(GitHubRepository{Owner: "camunda", Name: "zeebe"})
+
+	if err == nil || healthStateOf(err) != HealthUnauthorized {
+		t.Fatalf("Expected an unauthorized error, got %v", err)
+	}
+}
+
+func TestGitHubColor(t *testing.T) {
+	cases := map[string]string{
+		"success":   "green",
+		"skipped":   "green",
+		"failure":   "red",
+		"timed_out": "red",
+		"cancelled": "aborted",
+		"stale":     "grey",
+	}
+
+	for conclusion, expected := range cases {
+		if color := gitHubColor(conclusion); color != expected {
+			t.Errorf("Wrong color for conclusion '%s'. Expected: %s, got: %s", conclusion, expected, color)
+		}
+	}
+}
+
+func TestGitHubInstance_Validate(t *testing.T) {
+	instance := &GitHubInstance{Name: "camunda", Client: &TestGitHubClient{}, Repos: []GitHubRepository{{Owner: "camunda"}}}
+
+	if err := instance.Validate(); err == nil || !strings.Contains(err.Error(), "without name") {
+		t.Fatalf("Repository without name should be rejected, got %v", err)
+	}
+}
+
+// mockGitHubServer serves the fixtures of the 'camunda/zeebe' repository, if it's accessed with the token 'secret'.
+func mockGitHubServer(t *testing.T) *httptest.Server {
+	fixtures := map[string]string{
+		"/repos/camunda/zeebe":                        "testdata/github/repository.json",
+		"/repos/camunda/zeebe/actions/runs":           "testdata/github/workflow_runs.json",
+		"/repos/camunda/zeebe/actions/runs/1002/jobs": "testdata/github/workflow_jobs.json",
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/actions/runs") && r.URL.Query().Get("branch") != "main" {
+			t.Errorf("Expected runs of branch 'main', got '%s'", r.URL.Query().Get("branch"))
+		}
+
+		fileName, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Fatalf("Unable to read file: %s. Error: %s", fileName, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(content)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(f))
+}
@@ -0,0 +1,128 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// gitHubWebhookRepository is the repository an event of a GitHub webhook belongs to.
+type gitHubWebhookRepository struct {
+	Name          string `json:"name"`
+	DefaultBranch string `json:"default_branch"`
+	Owner         struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type gitHubWorkflowRunEvent struct {
+	Action      string                  `json:"action"`
+	WorkflowRun gitHubWorkflowRun       `json:"workflow_run"`
+	Repository  gitHubWebhookRepository `json:"repository"`
+}
+
+type gitHubCheckSuiteEvent struct {
+	Action     string `json:"action"`
+	CheckSuite struct {
+		HeadBranch string `json:"head_branch"`
+		App        struct {
+			Slug string `json:"slug"`
+		} `json:"app"`
+	} `json:"check_suite"`
+	Repository gitHubWebhookRepository `json:"repository"`
+}
+
+// ReceiveWebhook handles the 'workflow_run' and 'check_suite' events of a GitHub webhook, whose signature is verified
+// with the webhook secret. A completed workflow run is reported right away, its failed jobs are added by the next poll.
+// A completed check suite of GitHub Actions refreshes the workflows of the repository instead.
+func (g *GitHubInstance) ReceiveWebhook(r *http.Request, body []byte) ([]Job, error) {
+	if err := verifyWebhookSignature(body, r.Header.Get("X-Hub-Signature-256"), g.WebhookSecret); err != nil {
+		return nil, err
+	}
+
+	switch event := r.Header.Get("X-GitHub-Event"); event {
+	case "workflow_run":
+		e := &gitHubWorkflowRunEvent{}
+		if err := json.Unmarshal(body, e); err != nil {
+			return nil, webhookErrorf(http.StatusBadRequest, "Invalid GitHub %s event: %s", event, err)
+		}
+		return g.receiveWorkflowRun(e), nil
+	case "check_suite":
+		e := &gitHubCheckSuiteEvent{}
+		if err := json.Unmarshal(body, e); err != nil {
+			return nil, webhookErrorf(http.StatusBadRequest, "Invalid GitHub %s event: %s", event, err)
+		}
+		return g.receiveCheckSuite(e)
+	}
+	// further events, e.g. the 'ping' sent when creating the webhook, are acknowledged
+	return nil, nil
+}
+
+func (g *GitHubInstance) receiveWorkflowRun(e *gitHubWorkflowRunEvent) []Job {
+	run := e.WorkflowRun
+	if e.Action != "completed" || run.Event == "pull_request" {
+		return nil
+	}
+
+	var jobs []Job
+	for _, r := range g.watchedRepositories(e.Repository, run.HeadBranch) {
+		if !r.watches(run.Name, run.Path) {
+			continue
+		}
+
+		job := GitHubJob{
+			Name:      r.Name,
+			Workflow:  run.Name,
+			Branch:    run.HeadBranch,
+			URL:       run.HtmlUrl,
+			Color:     gitHubColor(run.Conclusion),
+			RunID:     run.ID,
+			RunNumber: run.RunNumber,
+			State:     run.Conclusion,
+		}
+		if run.HeadCommit != nil {
+			job.Commit = &GitHubCommit{Sha: run.HeadSha, Message: run.HeadCommit.Message, Author: run.HeadCommit.Author.Name}
+		}
+		jobs = append(jobs, gitHubJobOf(job))
+		break
+	}
+	return jobs
+}
+
+func (g *GitHubInstance) receiveCheckSuite(e *gitHubCheckSuiteEvent) ([]Job, error) {
+	// check suites of other apps don't belong to any workflow
+	if e.Action != "completed" || e.CheckSuite.App.Slug != "github-actions" {
+		return nil, nil
+	}
+
+	var jobs []Job
+	for _, r := range g.watchedRepositories(e.Repository, e.CheckSuite.HeadBranch) {
+		workflows, err := g.Client.Jobs(r)
+		if err != nil {
+			return nil, webhookErrorf(http.StatusBadGateway, "Unable to refresh the workflows of '%s': %s", r.Slug(), err)
+		}
+		for _, workflow := range workflows {
+			jobs = append(jobs, gitHubJobOf(workflow))
+		}
+	}
+	return jobs, nil
+}
+
+// watchedRepositories returns the watched repositories matching the repository and branch of an event.
+// Repositories without branch match the default branch.
+func (g *GitHubInstance) watchedRepositories(repository gitHubWebhookRepository, branch string) []GitHubRepository {
+	if !strings.EqualFold(repository.Owner.Login, g.Name) {
+		return nil
+	}
+
+	var watched []GitHubRepository
+	for _, r := range g.Repos {
+		if r.Name != repository.Name {
+			continue
+		}
+		if r.Branch == branch || (r.Branch == "" && branch == repository.DefaultBranch) {
+			watched = append(watched, r)
+		}
+	}
+	return watched
+}
@@ -0,0 +1,114 @@
+package dashboard
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGitHubInstance_ReceiveWebhook_WorkflowRun(t *testing.T) {
+	body, err := ioutil.ReadFile("testdata/webhooks/github_workflow_run.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := newWebhookGitHubInstance("", nil)
+
+	jobs, err := instance.ReceiveWebhook(newGitHubWebhookRequest("workflow_run", body, "secret"), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 1 || jobs[0].ID != "zeebe/CI@main" || jobs[0].Color != "red" || !jobs[0].Broken {
+		t.Fatalf("Completed workflow run should be reported, got %+v", jobs)
+	}
+	details := jobs[0].Details.(GitHubJob)
+	if details.RunNumber != 562 || details.State != "failure" || details.Commit == nil || details.Commit.Author != "Jane Doe" {
+		t.Fatalf("Details should describe the workflow run, got %+v", details)
+	}
+}
+
+func TestGitHubInstance_ReceiveWebhook_IgnoresUnwatchedRuns(t *testing.T) {
+	body, err := ioutil.ReadFile("testdata/webhooks/github_workflow_run.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]*GitHubInstance{
+		"other branch":   newWebhookGitHubInstance("stable/1.0", nil),
+		"other workflow": newWebhookGitHubInstance("", []string{"release.yml"}),
+		"other owner":    {Name: "zeebe-io", Repos: []GitHubRepository{{Owner: "zeebe-io", Name: "zeebe"}}, WebhookSecret: "secret"},
+	}
+	for name, instance := range tests {
+		jobs, err := instance.ReceiveWebhook(newGitHubWebhookRequest("workflow_run", body, "secret"), body)
+		if err != nil || len(jobs) != 0 {
+			t.Errorf("Workflow run of %s should be ignored, got %+v, %v", name, jobs, err)
+		}
+	}
+
+	pullRequest := []byte(strings.Replace(string(body), `"event": "push"`, `"event": "pull_request"`, 1))
+	jobs, err := newWebhookGitHubInstance("", nil).ReceiveWebhook(newGitHubWebhookRequest("workflow_run", pullRequest, "secret"), pullRequest)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Workflow run of pull request should be ignored, got %+v, %v", jobs, err)
+	}
+}
+
+func TestGitHubInstance_ReceiveWebhook_CheckSuite(t *testing.T) {
+	instance := newWebhookGitHubInstance("", nil)
+	instance.Client = &TestGitHubClient{jobs: map[string][]GitHubJob{"camunda/zeebe": {
+		{Name: "zeebe", Workflow: "CI", Branch: "main", Color: "green"},
+		{Name: "zeebe", Workflow: "Release", Branch: "main", Color: "red"},
+	}}}
+	body := []byte(`{"action": "completed", "check_suite": {"head_branch": "main", "app": {"slug": "github-actions"}},
+		"repository": {"name": "zeebe", "default_branch": "main", "owner": {"login": "camunda"}}}`)
+
+	jobs, err := instance.ReceiveWebhook(newGitHubWebhookRequest("check_suite", body, "secret"), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].Broken || !jobs[1].Broken {
+		t.Fatalf("Workflows of the repository should be refreshed, got %+v", jobs)
+	}
+
+	instance.Client = &TestGitHubClient{errors: map[string]error{"camunda/zeebe": errors.New("rate limit exceeded")}}
+	_, err = instance.ReceiveWebhook(newGitHubWebhookRequest("check_suite", body, "secret"), body)
+	if statusCodeOf(err) != http.StatusBadGateway {
+		t.Fatalf("Failed refresh should be reported, got %v", err)
+	}
+
+	otherApp := []byte(strings.Replace(string(body), "github-actions", "circleci-checks", 1))
+	jobs, err = instance.ReceiveWebhook(newGitHubWebhookRequest("check_suite", otherApp, "secret"), otherApp)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Check suites of other apps should be ignored, got %+v, %v", jobs, err)
+	}
+}
+
+func TestGitHubInstance_ReceiveWebhook_Rejected(t *testing.T) {
+	body := []byte(`{"zen": "Keep it logically awesome."}`)
+
+	_, err := newWebhookGitHubInstance("", nil).ReceiveWebhook(newGitHubWebhookRequest("ping", body, "other"), body)
+	if statusCodeOf(err) != http.StatusUnauthorized {
+		t.Fatalf("Webhook with wrong signature should be rejected, got %v", err)
+	}
+
+	jobs, err := newWebhookGitHubInstance("", nil).ReceiveWebhook(newGitHubWebhookRequest("ping", body, "secret"), body)
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Ping should be acknowledged, got %+v, %v", jobs, err)
+	}
+}
+
+func newWebhookGitHubInstance(branch string, workflows []string) *GitHubInstance {
+	return &GitHubInstance{
+		Name:          "camunda",
+		Repos:         []GitHubRepository{{Owner: "camunda", Name: "zeebe", Branch: branch, Workflows: workflows}},
+		WebhookSecret: "secret",
+	}
+}
+
+func newGitHubWebhookRequest(event string, body []byte, secret string) *http.Request {
+	request := newWebhookRequest("", "")
+	request.Header.Set("X-GitHub-Event", event)
+	request.Header.Set("X-Hub-Signature-256", signWebhook(body, secret))
+	return request
+}
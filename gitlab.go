@@ -0,0 +1,360 @@
+package dashboard
+
+import (
+	"fmt"
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	gitLabApi              = "/api/v4/"
+	gitLabPipelinesPerPage = 20
+)
+
+// GitLabInstance holds basic informations about a GitLab installation and the projects whose pipelines are watched.
+type GitLabInstance struct {
+	Name     string
+	Url      string
+	Projects []GitLabProject
+	Client   GitLab
+
+	// defaultRefs holds the default refs of the projects without ref, as they were resolved by the last fetch
+	mutex       sync.Mutex
+	defaultRefs map[string]string
+}
+
+func init() {
+	RegisterProvider("gitlab", newGitLabProvider)
+}
+
+// newGitLabProvider creates a GitLabInstance from the config keys 'url', 'accessToken' and 'projects'.
+// Projects without 'refs' are watched on their default branch.
+func newGitLabProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		Url         string
+		AccessToken string
+		Projects    []struct {
+			Path string
+			Refs []string
+		}
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	instance := &GitLabInstance{Name: name, Url: cfg.Url, Client: NewGitLabClient(cfg.Url, cfg.AccessToken)}
+	for _, p := range cfg.Projects {
+		// an empty ref stands for the default branch of the project
+		refs := p.Refs
+		if len(refs) == 0 {
+			refs = []string{""}
+		}
+
+		for _, ref := range refs {
+			instance.Projects = append(instance.Projects, GitLabProject{Path: p.Path, Ref: ref})
+		}
+	}
+
+	return instance, nil
+}
+
+func (g *GitLabInstance) Info() Aggregation {
+	return Aggregation{Type: "gitlab", Name: g.Name, Url: g.Url}
+}
+
+// Fetch retrieves the broken pipelines of the watched projects.
+func (g *GitLabInstance) Fetch() *InstanceAggregation {
+	gitLabAggregation := getBrokenBuildsForGitLabInstance(g)
+	aggregation := &InstanceAggregation{
+		Aggregation: gitLabAggregation.Aggregation,
+		Jobs:        make([]Job, 0, len(gitLabAggregation.Jobs)),
+	}
+
+	for _, job := range gitLabAggregation.Jobs {
+		aggregation.Jobs = append(aggregation.Jobs, Job{
+			ID:      job.ID(),
+			Name:    displayName(job.Name, job.Ref),
+			URL:     job.URL,
+			Color:   job.Color,
+			Running: job.Running,
+			Broken:  job.Error == "" && !job.IsSuccessful(),
+			Error:   job.Error,
+			Details: job,
+		})
+	}
+	return aggregation
+}
+
+// resolveDefaultRef remembers the default ref of a project without ref, if the job has one. Otherwise the job, e.g.
+// the placeholder of a failed request, gets the last known default ref, so that it's identified like its pipeline.
+func (g *GitLabInstance) resolveDefaultRef(project GitLabProject, job *GitLabJob) {
+	if project.Ref != "" {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if job.Ref != "" {
+		if g.defaultRefs == nil {
+			g.defaultRefs = make(map[string]string)
+		}
+		g.defaultRefs[project.Path] = job.Ref
+		return
+	}
+	job.Ref = g.defaultRefs[project.Path]
+}
+
+// Validate checks that the URL of the instance is usable and all projects are named.
+func (g *GitLabInstance) Validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("GitLab instance with URL '%s' has no name.", g.Url)
+	}
+	if err := validateUrl(g.Url); err != nil {
+		return fmt.Errorf("GitLab instance '%s' has an invalid URL: %s", g.Name, err)
+	}
+	if g.Client == nil {
+		return fmt.Errorf("GitLab instance '%s' has no client.", g.Name)
+	}
+	for _, p := range g.Projects {
+		if p.Path == "" {
+			return fmt.Errorf("GitLab instance '%s' has a project without path.", g.Name)
+		}
+	}
+	return nil
+}
+
+// Holds all dashboard relevant informations for a GitLab instance
+type GitLabAggregation struct {
+	Aggregation
+	Jobs  []GitLabJob `json:"jobs"`
+	Muted []GitLabJob `json:"muted"`
+}
+
+// gitLabAggregationOf converts the aggregation of an instance back into the GitLabAggregation served by the GitLab endpoint.
+func gitLabAggregationOf(aggregation *InstanceAggregation) *GitLabAggregation {
+	return &GitLabAggregation{
+		Aggregation: aggregation.Aggregation,
+		Jobs:        gitLabJobsOf(aggregation.Jobs),
+		Muted:       gitLabJobsOf(aggregation.Muted),
+	}
+}
+
+func gitLabJobsOf(jobs []Job) []GitLabJob {
+	gitLabJobs := make([]GitLabJob, 0, len(jobs))
+	for _, job := range jobs {
+		if gitLabJob, ok := job.Details.(GitLabJob); ok {
+			gitLabJob.Claim = job.Claim
+			gitLabJob.Mute = job.Mute
+			gitLabJob.Owner = job.Owner
+			gitLabJobs = append(gitLabJobs, gitLabJob)
+		}
+	}
+	return gitLabJobs
+}
+
+// GitLab is the high-level API for accessing the pipelines of a GitLab project.
+type GitLab interface {
+	// Job returns the state of the latest pipeline of the project on its ref.
+	Job(p GitLabProject) (GitLabJob, error)
+}
+
+// GitLabProject is a ref of a project whose pipelines are watched. The path is either the full path of the project,
+// e.g. 'camunda/zeebe', or its numeric id. An empty ref stands for the default branch of the project.
+type GitLabProject struct {
+	Path string
+	Ref  string
+}
+
+type GitLabJob struct {
+	Name string `json:"name"`
+	Ref  string `json:"ref"`
+	// URL points at the latest pipeline, or at the project if there is no pipeline yet.
+	URL        string `json:"url"`
+	Color      string `json:"color"`
+	PipelineID int    `json:"pipelineId,omitempty"`
+	State      string `json:"state,omitempty"`
+	Sha        string `json:"sha,omitempty"`
+	// Running is true, if the latest pipeline hasn't finished yet. The color is the one of the last completed pipeline then.
+	Running       bool                  `json:"running"`
+	LastCompleted *GitLabPipelineResult `json:"lastCompleted,omitempty"`
+	// FailedJobs are the jobs of the last completed pipeline which failed and are not allowed to fail.
+	FailedJobs []GitLabFailedJob `json:"failedJobs,omitempty"`
+	// FailedStages are the distinct stages of the failed jobs in the order of the jobs.
+	FailedStages []string `json:"failedStages,omitempty"`
+	// Error describes why the state of the pipeline couldn't be retrieved.
+	Error string `json:"error,omitempty"`
+	Claim *Claim `json:"claim,omitempty"`
+	Mute  *Mute  `json:"mute,omitempty"`
+	Owner *Team  `json:"owner,omitempty"`
+}
+
+// GitLabPipelineResult is the outcome of a completed pipeline.
+type GitLabPipelineResult struct {
+	PipelineID int    `json:"pipelineId"`
+	State      string `json:"state"`
+	URL        string `json:"url"`
+}
+
+// GitLabFailedJob is a failed job of a pipeline.
+type GitLabFailedJob struct {
+	Name  string `json:"name"`
+	Stage string `json:"stage"`
+	URL   string `json:"url"`
+}
+
+func (j GitLabJob) IsSuccessful() bool {
+	return j.Color == "green" || j.Color == ""
+}
+
+// ID returns the name which identifies the job inside its GitLab instance, i.e. 'project@ref'.
+func (j GitLabJob) ID() string {
+	return j.Name + "@" + j.Ref
+}
+
+// GitLabClient implements the GitLab interface using the v4 API of GitLab.
+type GitLabClient struct {
+	client *client.HTTPClient
+	url    string
+}
+
+type gitLabProjectResponse struct {
+	DefaultBranch string `json:"default_branch"`
+	WebUrl        string `json:"web_url"`
+}
+
+type gitLabPipeline struct {
+	ID     int    `json:"id"`
+	Sha    string `json:"sha"`
+	Ref    string `json:"ref"`
+	Status string `json:"status"`
+	WebUrl string `json:"web_url"`
+}
+
+type gitLabJob struct {
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	Status       string `json:"status"`
+	AllowFailure bool   `json:"allow_failure"`
+	WebUrl       string `json:"web_url"`
+}
+
+func (c *GitLabClient) Job(p GitLabProject) (GitLabJob, error) {
+	job := GitLabJob{Name: p.Path, Ref: p.Ref, URL: strings.TrimSuffix(c.url, "/") + "/" + p.Path}
+	project := url.PathEscape(p.Path)
+
+	if job.Ref == "" {
+		info := &gitLabProjectResponse{}
+		if err := c.get("projects/"+project, info, "GitLabProject"); err != nil {
+			return c.failed(job, err)
+		}
+		job.Ref = info.DefaultBranch
+	}
+
+	var pipelines []gitLabPipeline
+	pipelinesPath := fmt.Sprintf("projects/%s/pipelines?ref=%s&per_page=%d", project, url.QueryEscape(job.Ref), gitLabPipelinesPerPage)
+	if err := c.get(pipelinesPath, &pipelines, "GitLabPipelines"); err != nil {
+		return c.failed(job, err)
+	}
+	if len(pipelines) == 0 {
+		return job, nil
+	}
+
+	// the pipelines are ordered from newest to oldest
+	latest := pipelines[0]
+	job.PipelineID = latest.ID
+	job.State = latest.Status
+	job.Sha = latest.Sha
+	job.URL = latest.WebUrl
+
+	// a running pipeline doesn't have a result yet, so the job keeps the result of the last completed pipeline
+	completed := &latest
+	if IsGitLabPipelineRunning(latest.Status) {
+		job.Running = true
+		completed = nil
+		for i := range pipelines {
+			if !IsGitLabPipelineRunning(pipelines[i].Status) {
+				completed = &pipelines[i]
+				break
+			}
+		}
+		if completed == nil {
+			return job, nil
+		}
+		job.LastCompleted = &GitLabPipelineResult{PipelineID: completed.ID, State: completed.Status, URL: completed.WebUrl}
+	}
+
+	job.Color = gitLabColor(completed.Status)
+	if job.Color != "red" {
+		return job, nil
+	}
+
+	var jobs []gitLabJob
+	jobsPath := fmt.Sprintf("projects/%s/pipelines/%d/jobs?scope[]=failed&per_page=100", project, completed.ID)
+	if err := c.get(jobsPath, &jobs, "GitLabJobs"); err != nil {
+		job.Error = fmt.Sprintf("Unable to retrieve the failed jobs of pipeline #%d: %s", completed.ID, err)
+		return job, err
+	}
+
+	stages := make(map[string]bool)
+	for _, j := range jobs {
+		if j.AllowFailure {
+			continue
+		}
+		job.FailedJobs = append(job.FailedJobs, GitLabFailedJob{Name: j.Name, Stage: j.Stage, URL: j.WebUrl})
+		if !stages[j.Stage] {
+			stages[j.Stage] = true
+			job.FailedStages = append(job.FailedStages, j.Stage)
+		}
+	}
+	return job, nil
+}
+
+func (c *GitLabClient) failed(job GitLabJob, err error) (GitLabJob, error) {
+	job.Color = "grey"
+	job.Error = err.Error()
+	return job, err
+}
+
+func (c *GitLabClient) get(path string, v interface{}, component string) error {
+	response, err := c.client.GetFrom(path)
+	if err != nil {
+		return err
+	}
+	return processResponse(response, v, component)
+}
+
+// IsGitLabPipelineRunning returns true, if a pipeline in the given state hasn't finished yet.
+func IsGitLabPipelineRunning(status string) bool {
+	switch status {
+	case "created", "waiting_for_resource", "preparing", "pending", "running", "scheduled":
+		return true
+	}
+	return false
+}
+
+// gitLabColor maps the state of a completed pipeline onto the color of the job.
+func gitLabColor(status string) string {
+	switch status {
+	case "success", "skipped":
+		return "green"
+	case "failed":
+		return "red"
+	case "canceled":
+		return "aborted"
+	}
+	return "grey"
+}
+
+// NewGitLabClient returns a client for the GitLab installation at the given url, which authenticates with the
+// given personal access token. An empty token accesses the API anonymously.
+func NewGitLabClient(gitLabUrl string, token string) GitLab {
+	config := client.NewHTTPConfig(strings.TrimSuffix(gitLabUrl, "/")+gitLabApi, "", "", "application/json")
+	if token != "" {
+		config.SetHeader("PRIVATE-TOKEN", token)
+	}
+
+	return &GitLabClient{client: client.NewHTTPClient(config), url: gitLabUrl}
+}
@@ -0,0 +1,125 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGitLabClient_Job(t *testing.T) {
+	server := mockGitLabServer(t)
+	defer server.Close()
+
+	gc := NewGitLabClient(server.URL, "secret")
+	job, err := gc.Job(GitLabProject{Path: "camunda/optimize"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := GitLabJob{
+		Name:       "camunda/optimize",
+		Ref:        "master",
+		URL:        "https://gitlab.example.com/camunda/optimize/-/pipelines/1203",
+		Color:      "red",
+		PipelineID: 1203,
+		State:      "running",
+		Sha:        "c3f1e5a7b9d2f4a6c8e0b1d3f5a7c9e1b3d5f7a9",
+		Running:    true,
+		LastCompleted: &GitLabPipelineResult{
+			PipelineID: 1202,
+			State:      "failed",
+			URL:        "https://gitlab.example.com/camunda/optimize/-/pipelines/1202",
+		},
+		FailedJobs: []GitLabFailedJob{
+			{Name: "it:postgres", Stage: "test", URL: "https://gitlab.example.com/camunda/optimize/-/jobs/9001"},
+			{Name: "it:elasticsearch", Stage: "test", URL: "https://gitlab.example.com/camunda/optimize/-/jobs/9002"},
+			{Name: "docker", Stage: "package", URL: "https://gitlab.example.com/camunda/optimize/-/jobs/9004"},
+		},
+		FailedStages: []string{"test", "package"},
+	}
+
+	if !reflect.DeepEqual(job, expected) {
+		t.Fatalf("Wrong GitLabJob returned. Expected: %+v, got: %+v", expected, job)
+	}
+	if job.ID() != "camunda/optimize@master" {
+		t.Fatalf("Wrong id of GitLabJob, got %s", job.ID())
+	}
+}
+
+func TestGitLabClient_Unauthorized(t *testing.T) {
+	server := mockGitLabServer(t)
+	defer server.Close()
+
+	gc := NewGitLabClient(server.URL, "revoked")
+	job, err := gc.Job(GitLabProject{Path: "camunda/optimize", Ref: "master"})
+
+	if err == nil || healthStateOf(err) != HealthUnauthorized {
+		t.Fatalf("Expected an unauthorized error, got %v", err)
+	}
+	if job.Color != "grey" || job.Error != err.Error() {
+		t.Fatalf("Error should be part of the GitLabJob, got %+v", job)
+	}
+}
+
+func TestGitLabColor(t *testing.T) {
+	cases := map[string]string{
+		"success":  "green",
+		"skipped":  "green",
+		"failed":   "red",
+		"canceled": "aborted",
+		"manual":   "grey",
+	}
+
+	for status, expected := range cases {
+		if color := gitLabColor(status); color != expected {
+			t.Errorf("Wrong color for status '%s'. Expected: %s, got: %s", status, expected, color)
+		}
+	}
+
+	if !IsGitLabPipelineRunning("pending") || IsGitLabPipelineRunning("failed") {
+		t.Error("Only unfinished pipelines should be running")
+	}
+}
+
+func TestGitLabInstance_Validate(t *testing.T) {
+	instance := &GitLabInstance{Name: "GitLab", Url: "gitlab.example.com", Client: &TestGitLabClient{}}
+
+	if err := instance.Validate(); err == nil {
+		t.Fatal("Instance without http(s) URL should be rejected")
+	}
+}
+
+// mockGitLabServer serves the fixtures of the 'camunda/optimize' project, if it's accessed with the token 'secret'.
+func mockGitLabServer(t *testing.T) *httptest.Server {
+	fixtures := map[string]string{
+		"/api/v4/projects/camunda%2Foptimize":                     "testdata/gitlab/project.json",
+		"/api/v4/projects/camunda%2Foptimize/pipelines":           "testdata/gitlab/pipelines.json",
+		"/api/v4/projects/camunda%2Foptimize/pipelines/1202/jobs": "testdata/gitlab/failed_jobs.json",
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("ref") == "" && r.URL.EscapedPath() == "/api/v4/projects/camunda%2Foptimize/pipelines" {
+			t.Error("Pipelines should be requested for a ref")
+		}
+
+		fileName, ok := fixtures[r.URL.EscapedPath()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			t.Fatalf("Unable to read file: %s. Error: %s", fileName, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(content)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(f))
+}
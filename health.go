@@ -0,0 +1,153 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"github.com/shuheiktgw/go-travis"
+)
+
+// HealthState describes whether the dashboard is able to retrieve the state of an instance.
+type HealthState string
+
+const (
+	// HealthOk is used when all requests to the instance succeeded.
+	HealthOk HealthState = "ok"
+	// HealthDegraded is used when some, but not all requests to the instance failed.
+	HealthDegraded HealthState = "degraded"
+	// HealthDown is used when the instance is not reachable or answers with server errors.
+	HealthDown HealthState = "down"
+	// HealthUnauthorized is used when the instance rejects the configured credentials.
+	HealthUnauthorized HealthState = "unauthorized"
+	// HealthNotFound is used when the requested resource, e.g. the Broken view, doesn't exist.
+	HealthNotFound HealthState = "not-found"
+)
+
+// Health is the structured status of an instance.
+type Health struct {
+	State HealthState `json:"state"`
+	// Error is the message of the last error, if any.
+	Error string `json:"error,omitempty"`
+	// FailedRequest names the failing sub-request, e.g. 'queue', 'executors' or 'view' for Jenkins.
+	FailedRequest string `json:"failedRequest,omitempty"`
+	// LatencyMs is the time it took to fetch the instance in milliseconds.
+	LatencyMs int64 `json:"latencyMs"`
+	// LastSuccess is the time of the last fetch without any error.
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// IsOk returns true, if all requests to the instance succeeded.
+func (h Health) IsOk() bool {
+	return h.State == HealthOk
+}
+
+// IsAvailable returns true, if the instance could be reached at least partially.
+func (h Health) IsAvailable() bool {
+	return h.State == HealthOk || h.State == HealthDegraded
+}
+
+// newHealth computes the Health from the errors of the named sub-requests, which are ordered by their importance.
+// The most important failing sub-request determines the state. If other sub-requests succeeded, an unreachable
+// instance is reported as degraded.
+func newHealth(requests []string, errs []error, latency time.Duration) Health {
+	health := Health{State: HealthOk, LatencyMs: int64(latency / time.Millisecond)}
+
+	failures := 0
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failures++
+		if health.State == HealthOk {
+			health.State = healthStateOf(err)
+			health.Error = err.Error()
+			health.FailedRequest = requests[i]
+		}
+	}
+
+	if failures > 0 && failures < len(errs) && health.State == HealthDown {
+		health.State = HealthDegraded
+	}
+
+	return health
+}
+
+// newRatioHealth computes the Health from the errors of equally important requests, e.g. one per repository.
+// The instance is degraded if some requests failed, and only considered down if all of them failed.
+func newRatioHealth(requests []string, errs []error, latency time.Duration) Health {
+	health := newHealth(requests, errs, latency)
+
+	failures := 0
+	for _, err := range errs {
+		if err != nil {
+			failures++
+		}
+	}
+	if failures > 0 && failures < len(errs) {
+		health.State = HealthDegraded
+		health.Error = fmt.Sprintf("%d of %d requests failed, e.g. %s", failures, len(errs), health.Error)
+	}
+
+	return health
+}
+
+// failedHealth returns the Health of an instance which couldn't be fetched at all.
+func failedHealth(err error, latency time.Duration) Health {
+	return Health{
+		State:     healthStateOf(err),
+		Error:     err.Error(),
+		LatencyMs: int64(latency / time.Millisecond),
+	}
+}
+
+// healthStateOf maps the typed errors of the http package onto a HealthState.
+func healthStateOf(err error) HealthState {
+	switch e := err.(type) {
+	case *client.UnauthorizedError, client.UnauthorizedError:
+		return HealthUnauthorized
+	case *client.NotFoundError, client.NotFoundError:
+		return HealthNotFound
+	case *client.HttpError:
+		return healthStateOfStatusCode(e.StatusCode)
+	case client.HttpError:
+		return healthStateOfStatusCode(e.StatusCode)
+	case *travis.ErrorResponse:
+		if e.Response != nil {
+			return healthStateOfStatusCode(e.Response.StatusCode)
+		}
+	}
+	return HealthDown
+}
+
+func healthStateOfStatusCode(statusCode int) HealthState {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return HealthUnauthorized
+	case http.StatusNotFound:
+		return HealthNotFound
+	}
+	return HealthDown
+}
+
+// lastSuccess remembers the time of the last successful fetch of an instance across fetches.
+type lastSuccess struct {
+	mutex sync.Mutex
+	time  time.Time
+}
+
+// track records the given time, if the health is ok, and sets the time of the last successful fetch on the health.
+func (l *lastSuccess) track(health *Health, now time.Time) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if health.IsOk() {
+		l.time = now.UTC()
+	}
+	if !l.time.IsZero() {
+		t := l.time
+		health.LastSuccess = &t
+	}
+}
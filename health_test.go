@@ -0,0 +1,64 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+)
+
+func TestNewHealth(t *testing.T) {
+	requests := []string{"view", "queue", "executors"}
+	timeout := errors.New("timeout")
+	notFound := &client.NotFoundError{Message: "Resource not found."}
+
+	tests := []struct {
+		errs                  []error
+		expectedState         HealthState
+		expectedFailedRequest string
+	}{
+		{[]error{nil, nil, nil}, HealthOk, ""},
+		{[]error{nil, timeout, nil}, HealthDegraded, "queue"},
+		{[]error{timeout, timeout, timeout}, HealthDown, "view"},
+		{[]error{notFound, nil, nil}, HealthNotFound, "view"},
+		{[]error{nil, notFound, timeout}, HealthNotFound, "queue"},
+	}
+
+	for _, test := range tests {
+		health := newHealth(requests, test.errs, 42*time.Millisecond)
+
+		if health.State != test.expectedState {
+			t.Errorf("Wrong state for %v. Expected %s, got %s", test.errs, test.expectedState, health.State)
+		}
+		if health.FailedRequest != test.expectedFailedRequest {
+			t.Errorf("Wrong failed request for %v. Expected '%s', got '%s'", test.errs, test.expectedFailedRequest, health.FailedRequest)
+		}
+		if health.LatencyMs != 42 {
+			t.Errorf("Wrong latency. Expected 42, got %d", health.LatencyMs)
+		}
+	}
+}
+
+func TestLastSuccess_Track(t *testing.T) {
+	var tracker lastSuccess
+	first := time.Now()
+
+	down := Health{State: HealthDown}
+	tracker.track(&down, first)
+	if down.LastSuccess != nil {
+		t.Fatal("Time of last success must not be set before the first success.")
+	}
+
+	up := Health{State: HealthOk}
+	tracker.track(&up, first)
+	if up.LastSuccess == nil || !up.LastSuccess.Equal(first) {
+		t.Fatalf("Time of last success should be set. Expected %v, got %v", first, up.LastSuccess)
+	}
+
+	degraded := Health{State: HealthDegraded}
+	tracker.track(&degraded, first.Add(time.Minute))
+	if degraded.LastSuccess == nil || !degraded.LastSuccess.Equal(first) {
+		t.Fatalf("Time of last success should be kept. Expected %v, got %v", first, degraded.LastSuccess)
+	}
+}
@@ -40,6 +40,7 @@ type HTTPConfig struct {
 	username string
 	password string
 	accept   string
+	headers  map[string]string
 }
 
 // HTTPClient wraps the underlying http.Client and it's HTTPConfig.
@@ -100,6 +101,15 @@ func NewHTTPConfig(baseURL string, username string, password string, accept stri
 	return config
 }
 
+// SetHeader adds a header which is sent with every request, e.g. to authenticate with a token.
+func (c *HTTPConfig) SetHeader(name string, value string) *HTTPConfig {
+	if c.headers == nil {
+		c.headers = make(map[string]string)
+	}
+	c.headers[name] = value
+	return c
+}
+
 func DefaultHTTPConfig(baseURL string) *HTTPConfig {
 	return NewHTTPConfig(baseURL, "", "", jsonType)
 }
@@ -173,7 +183,7 @@ func (h *HTTPClient) DeleteFrom(path string) (*http.Response, error) {
 }
 
 func (h *HTTPClient) GetFromWithContext(ctx context.Context, path string) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password)
+	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password, h.config.headers)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +192,7 @@ func (h *HTTPClient) GetFromWithContext(ctx context.Context, path string) (*http
 }
 
 func (h *HTTPClient) PostToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password)
+	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password, h.config.headers)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +201,7 @@ func (h *HTTPClient) PostToWithContext(ctx context.Context, path string, body io
 }
 
 func (h *HTTPClient) PutToWithContext(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password)
+	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password, h.config.headers)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +210,7 @@ func (h *HTTPClient) PutToWithContext(ctx context.Context, path string, body io.
 }
 
 func (h *HTTPClient) DeleteFromWithContext(ctx context.Context, path string) (*http.Response, error) {
-	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password)
+	request, err := createRequest(ctx, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password, h.config.headers)
 	if err != nil {
 		return nil, err
 	}
@@ -209,19 +219,19 @@ func (h *HTTPClient) DeleteFromWithContext(ctx context.Context, path string) (*h
 }
 
 func (h *HTTPClient) GetRequest(path string) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password)
+	return createRequest(nil, h.config.baseURL, path, http.MethodGet, nil, h.config.username, h.config.password, h.config.headers)
 }
 
 func (h *HTTPClient) PostRequest(path string, body io.Reader) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password)
+	return createRequest(nil, h.config.baseURL, path, http.MethodPost, body, h.config.username, h.config.password, h.config.headers)
 }
 
 func (h *HTTPClient) PutRequest(path string, body io.Reader) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password)
+	return createRequest(nil, h.config.baseURL, path, http.MethodPut, body, h.config.username, h.config.password, h.config.headers)
 }
 
 func (h *HTTPClient) DeleteRequest(path string) (*http.Request, error) {
-	return createRequest(nil, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password)
+	return createRequest(nil, h.config.baseURL, path, http.MethodDelete, nil, h.config.username, h.config.password, h.config.headers)
 }
 
 //
@@ -231,7 +241,7 @@ func createDefaultContext(ctx context.Context) (context.Context, context.CancelF
 	return context.WithTimeout(context.Background(), defaultRequestTimeOut)
 }
 
-func createRequest(ctx context.Context, baseURL string, endpoint string, method string, body io.Reader, username string, password string) (*http.Request, error) {
+func createRequest(ctx context.Context, baseURL string, endpoint string, method string, body io.Reader, username string, password string, headers map[string]string) (*http.Request, error) {
 	// construct url by appending endpoint to base url
 	baseURL = strings.TrimSuffix(baseURL, "/")
 	endpoint = strings.TrimPrefix(endpoint, "/")
@@ -249,6 +259,10 @@ func createRequest(ctx context.Context, baseURL string, endpoint string, method
 		request.SetBasicAuth(username, password)
 	}
 
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
 	return request, nil
 }
 
@@ -146,6 +146,21 @@ func TestHttpClient_GetRequest(t *testing.T) {
 	assertURLIs(req.URL, fixtureBaseURL+"/path", t)
 }
 
+func TestHttpClient_SetHeader(t *testing.T) {
+	config := NewHTTPConfig(fixtureBaseURL, "", "", contentTypeJSON).
+		SetHeader("Authorization", "token secret").
+		SetHeader("Accept", "application/vnd.github.v3+json")
+	client := NewHTTPClient(config)
+	req, _ := client.GetRequest("path")
+
+	if req.Header.Get("Authorization") != "token secret" {
+		t.Errorf("Expected Authorization header 'token secret', got '%s'.", req.Header.Get("Authorization"))
+	}
+	if req.Header.Get("Accept") != "application/vnd.github.v3+json" {
+		t.Errorf("Expected configured header to override the default Accept header, got '%s'.", req.Header.Get("Accept"))
+	}
+}
+
 func TestHttpClient_PostRequest(t *testing.T) {
 
 }
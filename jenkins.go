@@ -8,6 +8,8 @@ import (
 	"log"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"strings"
 )
 
 const (
@@ -29,7 +31,150 @@ type JenkinsInstance struct {
 	Url           string
 	BrokenJobsUrl string
 	PublicUrl     string
-	Client        Jenkins
+	// WebhookToken authenticates the builds reported by the Jenkins Notification plugin, webhooks are rejected without it.
+	WebhookToken string
+	Client       Jenkins
+}
+
+func init() {
+	RegisterProvider("jenkins", newJenkinsProvider)
+}
+
+// newJenkinsProvider creates a JenkinsInstance from the config keys 'url', 'publicUrl', 'brokenJobsUrl',
+// 'username', 'password' and 'webhookToken'.
+func newJenkinsProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		Url           string
+		PublicUrl     string
+		BrokenJobsUrl string
+		Username      string
+		Password      string
+		WebhookToken  string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	return &JenkinsInstance{
+		Name:          name,
+		Url:           cfg.Url,
+		PublicUrl:     cfg.PublicUrl,
+		BrokenJobsUrl: cfg.BrokenJobsUrl,
+		WebhookToken:  cfg.WebhookToken,
+		Client:        NewJenkinsClient(cfg.Url, cfg.Username, cfg.Password),
+	}, nil
+}
+
+func (i *JenkinsInstance) Info() Aggregation {
+	return Aggregation{Type: "jenkins", Name: i.Name, Url: i.Url}
+}
+
+// Fetch retrieves the jobs of the Broken view along with the load of the instance. Successful jobs on the Broken
+// view are kept, but not marked as broken.
+func (i *JenkinsInstance) Fetch() *InstanceAggregation {
+	jenkinsAggregation := getBrokenBuildsForJenkinsInstance(i)
+	aggregation := &InstanceAggregation{
+		Aggregation: jenkinsAggregation.Aggregation,
+		Jobs:        make([]Job, 0, len(jenkinsAggregation.Jobs)),
+		Details: JenkinsDetails{
+			BrokenJobsUrl:  jenkinsAggregation.BrokenJobsUrl,
+			PublicUrl:      jenkinsAggregation.PublicUrl,
+			BusyExecutors:  jenkinsAggregation.BusyExecutors,
+			BuildQueueSize: jenkinsAggregation.BuildQueueSize,
+		},
+	}
+
+	for _, job := range jenkinsAggregation.Jobs {
+		aggregation.Jobs = append(aggregation.Jobs, i.jobOf(job))
+	}
+	return aggregation
+}
+
+// jobOf converts a job of the instance into a Job.
+func (i *JenkinsInstance) jobOf(job JenkinsJob) Job {
+	return Job{
+		ID:      job.ID(),
+		Name:    job.ID(),
+		URL:     job.URL,
+		Color:   job.Color,
+		Running: strings.HasSuffix(job.Color, "_anime"),
+		Broken:  !isJenkinsJobSuccessful(job),
+		Claim:   job.pluginClaim(i.Name),
+		Details: job,
+	}
+}
+
+// aggregationOf converts the aggregation of the instance back into the JenkinsAggregation served by the Jenkins endpoint.
+func (i *JenkinsInstance) aggregationOf(aggregation *InstanceAggregation) *JenkinsAggregation {
+	jenkinsAggregation := &JenkinsAggregation{
+		Aggregation:   aggregation.Aggregation,
+		BrokenJobsUrl: i.brokenJobsUrl(),
+		PublicUrl:     i.PublicUrl,
+		Jobs:          jenkinsJobsOf(aggregation.Jobs),
+		Muted:         jenkinsJobsOf(aggregation.Muted),
+	}
+	if details, ok := aggregation.Details.(JenkinsDetails); ok {
+		jenkinsAggregation.BusyExecutors = details.BusyExecutors
+		jenkinsAggregation.BuildQueueSize = details.BuildQueueSize
+	}
+	return jenkinsAggregation
+}
+
+func jenkinsJobsOf(jobs []Job) []JenkinsJob {
+	jenkinsJobs := make([]JenkinsJob, 0, len(jobs))
+	for _, job := range jobs {
+		if jenkinsJob, ok := job.Details.(JenkinsJob); ok {
+			jenkinsJob.Claim = job.Claim
+			jenkinsJob.Mute = job.Mute
+			jenkinsJob.Owner = job.Owner
+			jenkinsJobs = append(jenkinsJobs, jenkinsJob)
+		}
+	}
+	return jenkinsJobs
+}
+
+// Validate checks that the URLs of the instance are usable.
+func (i *JenkinsInstance) Validate() error {
+	if i.Name == "" {
+		return fmt.Errorf("Jenkins instance with URL '%s' has no name.", i.Url)
+	}
+	if err := validateUrl(i.Url); err != nil {
+		return fmt.Errorf("Jenkins instance '%s' has an invalid URL: %s", i.Name, err)
+	}
+	if i.BrokenJobsUrl != "" {
+		if err := validateUrl(i.BrokenJobsUrl); err != nil {
+			return fmt.Errorf("Jenkins instance '%s' has an invalid broken jobs URL: %s", i.Name, err)
+		}
+	}
+	if _, err := getBrokenJobsPath(i); err != nil {
+		return fmt.Errorf("Jenkins instance '%s' is misconfigured: %s", i.Name, err)
+	}
+	return nil
+}
+
+// brokenJobsUrl returns the URL of the Jenkins page containing the Broken view, which defaults to the instance URL.
+func (i *JenkinsInstance) brokenJobsUrl() string {
+	if i.BrokenJobsUrl == "" {
+		return i.Url
+	}
+	return i.BrokenJobsUrl
+}
+
+func validateUrl(rawUrl string) error {
+	if rawUrl == "" {
+		return fmt.Errorf("URL is missing")
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("'%s' is not an http(s) URL", rawUrl)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("'%s' has no host", rawUrl)
+	}
+	return nil
 }
 
 // JenkinsAggregations is a container for all retrieved JenkinsAggregation
@@ -45,6 +190,15 @@ type JenkinsAggregation struct {
 	BusyExecutors  int          `json:"busyExecutors"`
 	BuildQueueSize int          `json:"buildQueueSize"`
 	Jobs           []JenkinsJob `json:"jobs"`
+	Muted          []JenkinsJob `json:"muted"`
+}
+
+// JenkinsDetails holds the information of a Jenkins instance which is specific to Jenkins.
+type JenkinsDetails struct {
+	BrokenJobsUrl  string `json:"brokenJobsUrl"`
+	PublicUrl      string `json:"publicUrl"`
+	BusyExecutors  int    `json:"busyExecutors"`
+	BuildQueueSize int    `json:"buildQueueSize"`
 }
 
 // Jenkins is high-level API for accessing the underlying Jenkins instance.
@@ -99,22 +253,58 @@ func (q *JenkinsQueue) String() string {
 
 type JenkinsJob struct {
 	Name      string `json:"name"`
+	FullName  string `json:"fullName,omitempty"`
 	URL       string `json:"url"`
 	Color     string `json:"color"`
 	LastBuild struct {
-		Actions []struct {
-			FailCount          int           `json:"failCount,omitempty"`
-			SkipCount          int           `json:"skipCount,omitempty"`
-			TotalCount         int           `json:"totalCount,omitempty"`
-			FoundFailureCauses []interface{} `json:"foundFailureCauses,omitempty"`
-		} `json:"actions"`
+		Actions []JenkinsAction `json:"actions"`
 	} `json:"lastBuild"`
+	Claim *Claim `json:"claim,omitempty"`
+	Mute  *Mute  `json:"mute,omitempty"`
+	Owner *Team  `json:"owner,omitempty"`
+}
+
+// JenkinsAction holds the attributes of the build actions relevant for the dashboard,
+// i.e. test results, failure causes and claims.
+type JenkinsAction struct {
+	FailCount          int           `json:"failCount,omitempty"`
+	SkipCount          int           `json:"skipCount,omitempty"`
+	TotalCount         int           `json:"totalCount,omitempty"`
+	FoundFailureCauses []interface{} `json:"foundFailureCauses,omitempty"`
+	Claimed            bool          `json:"claimed,omitempty"`
+	ClaimedBy          string        `json:"claimedBy,omitempty"`
+	Reason             string        `json:"reason,omitempty"`
 }
 
 func (j *JenkinsJob) String() string {
 	return fmt.Sprintf("%#v", j)
 }
 
+// ID returns the name which identifies the job inside its Jenkins instance.
+// Jobs inside of folders are identified by their full name.
+func (j *JenkinsJob) ID() string {
+	if j.FullName != "" {
+		return j.FullName
+	}
+	return j.Name
+}
+
+// pluginClaim returns the claim of the last build made with the Jenkins Claim plugin, if the plugin is installed.
+func (j *JenkinsJob) pluginClaim(instance string) *Claim {
+	for _, action := range j.LastBuild.Actions {
+		if action.Claimed {
+			return &Claim{
+				Instance: instance,
+				Job:      j.ID(),
+				User:     action.ClaimedBy,
+				Note:     action.Reason,
+				Source:   claimSourceJenkins,
+			}
+		}
+	}
+	return nil
+}
+
 // JenkinsView represents a view inside Jenkins including all jobs on it.
 type JenkinsView struct {
 	Jobs []JenkinsJob `json:"jobs"`
@@ -0,0 +1,118 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// jenkinsNotification is the JSON payload sent by the Jenkins Notification plugin for every phase of a build.
+type jenkinsNotification struct {
+	Name string `json:"name"`
+	// URL is the path of the job relative to Jenkins, e.g. 'job/docs/job/master/'.
+	URL   string `json:"url"`
+	Build struct {
+		FullURL string `json:"full_url"`
+		Number  int    `json:"number"`
+		Phase   string `json:"phase"`
+		Status  string `json:"status"`
+	} `json:"build"`
+}
+
+// ReceiveWebhook handles the notifications of the Jenkins Notification plugin, which are authenticated by the
+// webhook token. Only completed builds are reported, as the color of a running job is the one of its last build.
+// Builds of jobs outside the folder of the Broken view are ignored, as the dashboard doesn't show them.
+func (i *JenkinsInstance) ReceiveWebhook(r *http.Request, body []byte) ([]Job, error) {
+	if err := verifyWebhookToken(r, i.WebhookToken); err != nil {
+		return nil, err
+	}
+
+	notification := &jenkinsNotification{}
+	if err := json.Unmarshal(body, notification); err != nil {
+		return nil, webhookErrorf(http.StatusBadRequest, "Invalid Jenkins notification: %s", err)
+	}
+	if notification.URL == "" {
+		return nil, webhookErrorf(http.StatusBadRequest, "Jenkins notification has no job URL.")
+	}
+	if notification.Build.Phase != "COMPLETED" && notification.Build.Phase != "FINALIZED" {
+		return nil, nil
+	}
+	if !i.inBrokenJobsFolder(notification.URL) {
+		return nil, nil
+	}
+
+	job := JenkinsJob{
+		Name:     notification.Name,
+		FullName: jenkinsFullNameOf(notification.URL),
+		URL:      strings.TrimSuffix(i.Url, "/") + "/" + notification.URL,
+		Color:    jenkinsColorOf(notification.Build.Status),
+	}
+	return []Job{i.jobOf(job)}, nil
+}
+
+// mergeWebhookJob updates the color of the stored job with the reported one. The notification carries nothing but the
+// status of the build, so the failure causes and the claim of the last poll are kept until the next one.
+func (i *JenkinsInstance) mergeWebhookJob(stored Job, reported Job) Job {
+	merged := stored
+	merged.Color = reported.Color
+	merged.Running = reported.Running
+	merged.Broken = reported.Broken
+	if details, ok := stored.Details.(JenkinsJob); ok {
+		details.Color = reported.Color
+		merged.Details = details
+	}
+	return merged
+}
+
+// keepsWebhookJob returns true for every reported job, as the Broken view keeps successful jobs as well and the
+// notifications of jobs outside of its folder are ignored already.
+func (i *JenkinsInstance) keepsWebhookJob(job Job) bool {
+	return true
+}
+
+// inBrokenJobsFolder returns true, if the job with the given relative URL is inside the folder whose Broken view is
+// polled, e.g. 'job/docs/job/master/' for the broken jobs URL 'http://ci:8080/job/docs'.
+func (i *JenkinsInstance) inBrokenJobsFolder(jobUrl string) bool {
+	folder, err := getBrokenJobsPath(i)
+	if err != nil {
+		return false
+	}
+	folder = strings.Trim(folder, "/")
+	return folder == "" || strings.HasPrefix(strings.Trim(jobUrl, "/")+"/", folder+"/")
+}
+
+// jenkinsFullNameOf returns the full name of a job from its relative URL, e.g. 'docs/master' for
+// 'job/docs/job/master/'.
+func jenkinsFullNameOf(jobUrl string) string {
+	var names []string
+	segments := strings.Split(strings.Trim(jobUrl, "/"), "/")
+	for index := 1; index < len(segments); index += 2 {
+		if segments[index-1] != "job" {
+			continue
+		}
+		name, err := url.PathUnescape(segments[index])
+		if err != nil {
+			name = segments[index]
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, "/")
+}
+
+// jenkinsColorOf maps the result of a build onto the color Jenkins shows for its job.
+func jenkinsColorOf(status string) string {
+	switch status {
+	case "SUCCESS":
+		return "blue"
+	case "UNSTABLE":
+		return "yellow"
+	case "FAILURE":
+		return "red"
+	case "ABORTED":
+		return "aborted"
+	case "NOT_BUILT":
+		return "notbuilt"
+	}
+	return "grey"
+}
@@ -0,0 +1,132 @@
+package dashboard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestJenkinsInstance_ReceiveWebhook(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/webhooks/jenkins_completed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := &JenkinsInstance{Name: "Docs", Url: "https://ci.example.com/", WebhookToken: "secret"}
+
+	jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 1 {
+		t.Fatalf("Completed build should be reported, got %+v", jobs)
+	}
+	job := jobs[0]
+	if job.ID != "docs/master" || job.Color != "red" || !job.Broken || job.URL != "https://ci.example.com/job/docs/job/master/" {
+		t.Fatalf("Wrong job reported, got %+v", job)
+	}
+	if details, ok := job.Details.(JenkinsJob); !ok || details.Name != "master" {
+		t.Fatalf("Details should hold the JenkinsJob, got %+v", job.Details)
+	}
+}
+
+func TestJenkinsInstance_ReceiveWebhook_IgnoresStartedBuilds(t *testing.T) {
+	instance := &JenkinsInstance{Name: "Docs", Url: "https://ci.example.com", WebhookToken: "secret"}
+	body := `{"name": "master", "url": "job/master/", "build": {"phase": "STARTED", "number": 43}}`
+
+	jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), []byte(body))
+
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Started build should be ignored, got %+v, %v", jobs, err)
+	}
+}
+
+func TestJenkinsInstance_ReceiveWebhook_Rejected(t *testing.T) {
+	instance := &JenkinsInstance{Name: "Docs", Url: "https://ci.example.com", WebhookToken: "secret"}
+
+	_, err := instance.ReceiveWebhook(newWebhookRequest("?token=guess", ""), []byte("{}"))
+	if statusCodeOf(err) != http.StatusUnauthorized {
+		t.Fatalf("Webhook with wrong token should be rejected, got %v", err)
+	}
+
+	_, err = instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), []byte("<html>"))
+	if statusCodeOf(err) != http.StatusBadRequest || !strings.Contains(err.Error(), "Invalid Jenkins notification") {
+		t.Fatalf("Invalid notification should be rejected, got %v", err)
+	}
+}
+
+func TestJenkinsFullNameOf(t *testing.T) {
+	cases := map[string]string{
+		"job/master/":                  "master",
+		"job/docs/job/master/":         "docs/master",
+		"/job/docs/job/release%2F7.12": "docs/release/7.12",
+		"":                             "",
+	}
+	for jobUrl, expected := range cases {
+		if name := jenkinsFullNameOf(jobUrl); name != expected {
+			t.Errorf("Wrong full name of '%s'. Expected: %s, got: %s", jobUrl, expected, name)
+		}
+	}
+}
+
+func TestJenkinsColorOf(t *testing.T) {
+	cases := map[string]string{
+		"SUCCESS":  "blue",
+		"UNSTABLE": "yellow",
+		"FAILURE":  "red",
+		"ABORTED":  "aborted",
+		"":         "grey",
+	}
+	for status, expected := range cases {
+		if color := jenkinsColorOf(status); color != expected {
+			t.Errorf("Wrong color of status '%s'. Expected: %s, got: %s", status, expected, color)
+		}
+	}
+}
+
+func TestJenkinsInstance_ReceiveWebhook_IgnoresJobsOutsideFolder(t *testing.T) {
+	instance := &JenkinsInstance{Name: "Docs", Url: "https://ci.example.com", BrokenJobsUrl: "https://ci.example.com/job/docs", WebhookToken: "secret"}
+	body := `{"name": "master", "url": "job/%s/job/master/", "build": {"phase": "COMPLETED", "status": "FAILURE"}}`
+
+	jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), []byte(fmt.Sprintf(body, "docs-preview")))
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Job outside the folder should be ignored, got %+v, %v", jobs, err)
+	}
+
+	jobs, err = instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), []byte(fmt.Sprintf(body, "docs")))
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "docs/master" {
+		t.Fatalf("Job inside the folder should be reported, got %+v, %v", jobs, err)
+	}
+}
+
+func TestDashboard_ReceiveWebhook_MergesJenkinsJob(t *testing.T) {
+	job := JenkinsJob{Name: "master", FullName: "docs/master", Color: "red", URL: "https://ci.example.com/job/docs/job/master/"}
+	job.LastBuild.Actions = []JenkinsAction{
+		{FoundFailureCauses: []interface{}{map[string]interface{}{"description": "Timeout"}}},
+		{Claimed: true, ClaimedBy: "jane"},
+	}
+	client := &TestJenkinsClient{queue: &JenkinsQueue{}, jobs: []JenkinsJob{job}}
+	instance := New(&JenkinsInstance{Name: "Docs", Url: "https://ci.example.com", WebhookToken: "secret", Client: client})
+	instance.setPolling(true)
+	instance.Fetch()
+
+	content, err := ioutil.ReadFile("testdata/webhooks/jenkins_completed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := strings.Replace(string(content), `"FAILURE"`, `"UNSTABLE"`, 1)
+	if err := instance.ReceiveWebhook("Docs", newWebhookRequest("?token=secret", body)); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := instance.Fetch()[0].Jobs
+	if len(jobs) != 1 || jobs[0].Color != "yellow" || jobs[0].Claim == nil || jobs[0].Claim.User != "jane" {
+		t.Fatalf("Status should be merged into the polled job, got %+v", jobs)
+	}
+	details := jobs[0].Details.(JenkinsJob)
+	if details.Color != "yellow" || len(details.LastBuild.Actions) != 2 {
+		t.Fatalf("Details of the polled job should be kept, got %+v", details)
+	}
+}
@@ -0,0 +1,267 @@
+package dashboard
+
+import (
+	"fmt"
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// defaultJSONColors maps the statuses commonly reported by tools onto the colors of the jobs.
+var defaultJSONColors = map[string]string{
+	"success":    "green",
+	"successful": "green",
+	"passed":     "green",
+	"pass":       "green",
+	"ok":         "green",
+	"green":      "green",
+	"true":       "green",
+	"failure":    "red",
+	"failed":     "red",
+	"fail":       "red",
+	"error":      "red",
+	"errored":    "red",
+	"broken":     "red",
+	"red":        "red",
+	"false":      "red",
+	"aborted":    "aborted",
+	"canceled":   "aborted",
+	"cancelled":  "aborted",
+}
+
+// JSONInstance polls the status of jobs from an arbitrary URL serving JSON, e.g. of an in-house tool.
+// The jobs and their fields are selected from the response with JSONPath expressions.
+type JSONInstance struct {
+	JSONMapping
+	Name   string
+	Url    string
+	Client *client.HTTPClient
+}
+
+// JSONMapping maps the status of jobs reported as JSON onto the jobs of an instance.
+type JSONMapping struct {
+	// Jobs selects the jobs from the JSON.
+	Jobs *JSONPath
+	// Fields select the attributes of every job, relative to the job.
+	Fields JSONFields
+	// Colors maps the lowercase statuses onto the colors of the jobs, in addition to the common statuses.
+	Colors map[string]string
+}
+
+// JSONFields are the paths to the attributes of a job. Running is optional and considered true, if it is 'true'.
+type JSONFields struct {
+	Name    *JSONPath
+	URL     *JSONPath
+	Status  *JSONPath
+	Running *JSONPath
+}
+
+// JSONJob is a job of a JSONInstance.
+type JSONJob struct {
+	Name   string `json:"name"`
+	URL    string `json:"url,omitempty"`
+	Status string `json:"status"`
+}
+
+func init() {
+	RegisterProvider("json", newJSONProvider)
+}
+
+// jsonMappingConfig is the configuration of a JSONMapping.
+type jsonMappingConfig struct {
+	Jobs   string
+	Fields struct {
+		Name    string
+		Url     string
+		Status  string
+		Running string
+	}
+	Colors map[string]string
+}
+
+// newJSONMapping creates a JSONMapping from the config keys 'jobs', 'fields' and 'colors'. Without 'jobs' the
+// JSON has to be an array of jobs. The 'fields' default to the 'name', 'url' and 'status' of the jobs.
+func newJSONMapping(cfg jsonMappingConfig) (JSONMapping, error) {
+	mapping := JSONMapping{Colors: make(map[string]string)}
+	for status, color := range cfg.Colors {
+		mapping.Colors[strings.ToLower(status)] = color
+	}
+
+	paths := []struct {
+		path       **JSONPath
+		expression string
+		fallback   string
+	}{
+		{&mapping.Jobs, cfg.Jobs, "$"},
+		{&mapping.Fields.Name, cfg.Fields.Name, "$.name"},
+		{&mapping.Fields.URL, cfg.Fields.Url, "$.url"},
+		{&mapping.Fields.Status, cfg.Fields.Status, "$.status"},
+		{&mapping.Fields.Running, cfg.Fields.Running, ""},
+	}
+	for _, p := range paths {
+		expression := p.expression
+		if expression == "" {
+			expression = p.fallback
+		}
+		if expression == "" {
+			continue
+		}
+		path, err := ParseJSONPath(expression)
+		if err != nil {
+			return mapping, err
+		}
+		*p.path = path
+	}
+
+	return mapping, nil
+}
+
+// newJSONProvider creates a JSONInstance from the config keys 'url', 'username', 'password' and 'headers',
+// along with the ones of its JSONMapping.
+func newJSONProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		jsonMappingConfig
+		Url      string
+		Username string
+		Password string
+		Headers  map[string]string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	mapping, err := newJSONMapping(cfg.jsonMappingConfig)
+	if err != nil {
+		return nil, err
+	}
+	instance := &JSONInstance{JSONMapping: mapping, Name: name, Url: cfg.Url}
+
+	// the status URL is split into the base URL of the client and the path requested from it
+	if u, err := url.Parse(cfg.Url); err == nil {
+		httpConfig := client.NewHTTPConfig(u.Scheme+"://"+u.Host, cfg.Username, cfg.Password, "application/json")
+		for header, value := range cfg.Headers {
+			httpConfig.SetHeader(header, value)
+		}
+		instance.Client = client.NewHTTPClient(httpConfig)
+	}
+
+	return instance, nil
+}
+
+func (j *JSONInstance) Info() Aggregation {
+	return Aggregation{Type: "json", Name: j.Name, Url: j.Url}
+}
+
+// Validate checks that the URL of the instance is usable and all fields can be selected.
+func (j *JSONInstance) Validate() error {
+	if j.Name == "" {
+		return fmt.Errorf("JSON instance with URL '%s' has no name.", j.Url)
+	}
+	if err := validateUrl(j.Url); err != nil {
+		return fmt.Errorf("JSON instance '%s' has an invalid URL: %s", j.Name, err)
+	}
+	if j.Client == nil {
+		return fmt.Errorf("JSON instance '%s' has no client.", j.Name)
+	}
+	if err := j.JSONMapping.validate(); err != nil {
+		return fmt.Errorf("JSON instance '%s' is misconfigured: %s", j.Name, err)
+	}
+	return nil
+}
+
+// Fetch requests the status of the jobs and returns the ones which aren't successful.
+func (j *JSONInstance) Fetch() *InstanceAggregation {
+	start := time.Now()
+	aggregation := &InstanceAggregation{Aggregation: j.Info(), Jobs: make([]Job, 0)}
+
+	var response interface{}
+	err := j.get(&response)
+	if err != nil {
+		log.Printf("[WARN] %s (status): %s", j.Name, err)
+	}
+	aggregation.Health = newHealth([]string{"status"}, []error{err}, time.Since(start))
+	if err != nil {
+		return aggregation
+	}
+
+	aggregation.Jobs = j.brokenJobs(response)
+	return aggregation
+}
+
+func (j *JSONInstance) get(v interface{}) error {
+	u, err := url.Parse(j.Url)
+	if err != nil {
+		return err
+	}
+	response, err := j.Client.GetFrom(u.RequestURI())
+	if err != nil {
+		return err
+	}
+	return processResponse(response, v, "JSONStatus")
+}
+
+func (m JSONMapping) validate() error {
+	if m.Jobs == nil || m.Fields.Name == nil || m.Fields.Status == nil {
+		return fmt.Errorf("jobs, name or status are not configured")
+	}
+	return nil
+}
+
+// brokenJobs selects the jobs from the given decoded JSON and returns the ones which aren't successful.
+func (m JSONMapping) brokenJobs(document interface{}) []Job {
+	values := m.Jobs.Select(document)
+	// a path to the array of jobs, instead of to the jobs themselves, is accepted as well
+	if len(values) == 1 {
+		if array, ok := values[0].([]interface{}); ok {
+			values = array
+		}
+	}
+
+	jobs := make([]Job, 0)
+	for index, value := range values {
+		job := m.job(index, value)
+		if job.Color != "green" && job.Color != "" {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// job maps the fields of the job at the given index of the response onto a Job.
+func (m JSONMapping) job(index int, value interface{}) Job {
+	details := JSONJob{}
+	details.Name, _ = m.Fields.Name.SelectString(value)
+	if m.Fields.URL != nil {
+		details.URL, _ = m.Fields.URL.SelectString(value)
+	}
+	status, hasStatus := m.Fields.Status.SelectString(value)
+	details.Status = status
+
+	job := Job{ID: details.Name, Name: details.Name, URL: details.URL, Details: details}
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("#%d", index)
+		job.Name = job.ID
+		job.Error = fmt.Sprintf("No name found at '%s'", m.Fields.Name)
+	}
+	if m.Fields.Running != nil {
+		running, _ := m.Fields.Running.SelectString(value)
+		job.Running = running == "true"
+	}
+
+	switch {
+	case !hasStatus:
+		job.Color = "grey"
+		job.Error = fmt.Sprintf("No status found at '%s'", m.Fields.Status)
+	case m.Colors[strings.ToLower(status)] != "":
+		job.Color = m.Colors[strings.ToLower(status)]
+	case defaultJSONColors[strings.ToLower(status)] != "":
+		job.Color = defaultJSONColors[strings.ToLower(status)]
+	default:
+		job.Color = "grey"
+		job.Error = fmt.Sprintf("Unknown status '%s'", status)
+	}
+	job.Broken = !job.Unknown()
+	return job
+}
@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONInstance_Fetch(t *testing.T) {
+	content, err := ioutil.ReadFile("testdata/json/nightly.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := mockServer(http.StatusOK, "application/json", string(content), func(r *http.Request) {
+		if r.URL.RequestURI() != "/api/status?format=json" {
+			t.Errorf("Wrong status URL requested, got %s", r.URL.RequestURI())
+		}
+	})
+	defer server.Close()
+
+	instance := newNightlyInstance(t, server.URL+"/api/status?format=json")
+	aggregation := instance.Fetch()
+
+	if !aggregation.Health.IsOk() || aggregation.Type != "json" {
+		t.Fatalf("Instance should be healthy, got %+v", aggregation)
+	}
+
+	expected := []Job{
+		{
+			ID:      "engine-it",
+			Name:    "engine-it",
+			URL:     "https://nightly.example.com/reports/engine-it",
+			Color:   "red",
+			Broken:  true,
+			Details: JSONJob{Name: "engine-it", URL: "https://nightly.example.com/reports/engine-it", Status: "FAILED"},
+		},
+		{
+			ID:      "rolling-update",
+			Name:    "rolling-update",
+			URL:     "https://nightly.example.com/reports/rolling-update",
+			Color:   "amber",
+			Running: true,
+			Broken:  true,
+			Details: JSONJob{Name: "rolling-update", URL: "https://nightly.example.com/reports/rolling-update", Status: "FLAKY"},
+		},
+		{
+			ID:      "performance",
+			Name:    "performance",
+			URL:     "https://nightly.example.com/reports/performance",
+			Color:   "grey",
+			Error:   "Unknown status 'SKIPPED-BY-OPERATOR'",
+			Details: JSONJob{Name: "performance", URL: "https://nightly.example.com/reports/performance", Status: "SKIPPED-BY-OPERATOR"},
+		},
+		{
+			ID:      "migration",
+			Name:    "migration",
+			Color:   "grey",
+			Error:   "No status found at '$.result'",
+			Details: JSONJob{Name: "migration"},
+		},
+	}
+	if !reflect.DeepEqual(aggregation.Jobs, expected) {
+		t.Fatalf("Wrong jobs returned. Expected: %+v, got: %+v", expected, aggregation.Jobs)
+	}
+}
+
+func TestJSONInstance_FetchUnavailable(t *testing.T) {
+	server := mockServer(http.StatusUnauthorized, "application/json", "", nil)
+	defer server.Close()
+
+	aggregation := newNightlyInstance(t, server.URL+"/api/status").Fetch()
+
+	if aggregation.Health.State != HealthUnauthorized || len(aggregation.Jobs) != 0 {
+		t.Fatalf("Instance should be unauthorized, got %+v", aggregation)
+	}
+}
+
+func TestJSONInstance_Validate(t *testing.T) {
+	provider, err := NewProvider("json", "Nightly", ProviderConfig{"url": "nightly.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := provider.Validate(); err == nil || !strings.Contains(err.Error(), "invalid URL") {
+		t.Fatalf("Instance without http(s) URL should be rejected, got %v", err)
+	}
+
+	_, err = NewProvider("json", "Nightly", ProviderConfig{"url": "https://nightly.example.com", "jobs": "$..results"})
+	if err == nil || !strings.Contains(err.Error(), "Invalid JSONPath") {
+		t.Fatalf("Invalid JSONPath should be rejected, got %v", err)
+	}
+}
+
+func newNightlyInstance(t *testing.T, url string) Provider {
+	provider, err := NewProvider("json", "Nightly", ProviderConfig{
+		"url":  url,
+		"jobs": "$.results[*]",
+		"fields": map[string]interface{}{
+			"name":    "$.suite",
+			"url":     "links.report",
+			"status":  "$.result",
+			"running": "$.inProgress",
+		},
+		"colors": map[string]interface{}{"Flaky": "amber"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
@@ -0,0 +1,169 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a subset of JSONPath which selects values from decoded JSON, e.g. '$.results[*].name'.
+// It supports child names in dot and bracket notation, array indices, which count from the end if negative,
+// and the wildcard '*' for all elements of an array or all values of an object. The root may be written as
+// '$' or '@', or be left out.
+type JSONPath struct {
+	expression string
+	steps      []jsonPathStep
+}
+
+type jsonPathStep struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ParseJSONPath parses the given expression into a JSONPath.
+func ParseJSONPath(expression string) (*JSONPath, error) {
+	path := &JSONPath{expression: expression}
+
+	rest := strings.TrimSpace(expression)
+	if strings.HasPrefix(rest, "$") || strings.HasPrefix(rest, "@") {
+		rest = rest[1:]
+	} else if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+
+	for rest != "" {
+		var step jsonPathStep
+		var err error
+		switch rest[0] {
+		case '.':
+			step, rest, err = parseJSONPathName(rest[1:])
+		case '[':
+			step, rest, err = parseJSONPathBracket(rest[1:])
+		default:
+			err = fmt.Errorf("unexpected '%c'", rest[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid JSONPath '%s': %s", expression, err)
+		}
+		path.steps = append(path.steps, step)
+	}
+
+	return path, nil
+}
+
+func parseJSONPathName(rest string) (jsonPathStep, string, error) {
+	end := strings.IndexAny(rest, ".[")
+	if end < 0 {
+		end = len(rest)
+	}
+	name := rest[:end]
+
+	switch name {
+	case "":
+		return jsonPathStep{}, "", fmt.Errorf("name is missing, recursive descent is not supported")
+	case "*":
+		return jsonPathStep{wildcard: true}, rest[end:], nil
+	}
+	return jsonPathStep{name: name}, rest[end:], nil
+}
+
+func parseJSONPathBracket(rest string) (jsonPathStep, string, error) {
+	if rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		end := strings.IndexByte(rest[1:], rest[0])
+		if end < 0 || !strings.HasPrefix(rest[end+2:], "]") {
+			return jsonPathStep{}, "", fmt.Errorf("unterminated name")
+		}
+		return jsonPathStep{name: rest[1 : end+1]}, rest[end+3:], nil
+	}
+
+	end := strings.IndexByte(rest, ']')
+	if end < 0 {
+		return jsonPathStep{}, "", fmt.Errorf("missing ']'")
+	}
+	if rest[:end] == "*" {
+		return jsonPathStep{wildcard: true}, rest[end+1:], nil
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(rest[:end]))
+	if err != nil {
+		return jsonPathStep{}, "", fmt.Errorf("'%s' is neither an index, a quoted name nor '*'", rest[:end])
+	}
+	return jsonPathStep{index: index, isIndex: true}, rest[end+1:], nil
+}
+
+func (p *JSONPath) String() string {
+	return p.expression
+}
+
+// Select returns all values of the given decoded JSON which are matched by the path.
+func (p *JSONPath) Select(value interface{}) []interface{} {
+	values := []interface{}{value}
+	for _, step := range p.steps {
+		var selected []interface{}
+		for _, v := range values {
+			selected = append(selected, step.selectFrom(v)...)
+		}
+		values = selected
+	}
+	return values
+}
+
+func (s jsonPathStep) selectFrom(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			values := make([]interface{}, 0, len(v))
+			for _, key := range keys {
+				values = append(values, v[key])
+			}
+			return values
+		}
+		if child, ok := v[s.name]; ok && !s.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		index := s.index
+		if index < 0 {
+			index += len(v)
+		}
+		if s.isIndex && index >= 0 && index < len(v) {
+			return []interface{}{v[index]}
+		}
+	}
+	return nil
+}
+
+// SelectString returns the first value matched by the path as string. Numbers and booleans are formatted, objects
+// and arrays are returned as JSON. It returns false, if no value or only null is matched.
+func (p *JSONPath) SelectString(value interface{}) (string, bool) {
+	values := p.Select(value)
+	if len(values) == 0 || values[0] == nil {
+		return "", false
+	}
+
+	switch v := values[0].(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	content, err := json.Marshal(values[0])
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
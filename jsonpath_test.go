@@ -0,0 +1,87 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPath_Select(t *testing.T) {
+	var document interface{}
+	json.Unmarshal([]byte(`{
+		"results": [
+			{"name": "engine", "duration": 12.5, "passed": true, "tags": ["it"]},
+			{"name": "webapps", "duration": 3, "passed": false, "tags": []}
+		],
+		"report server": {"url": "https://reports.example.com"}
+	}`), &document)
+
+	cases := map[string][]interface{}{
+		"$.results[0].name":              {"engine"},
+		"results[-1].name":               {"webapps"},
+		"@.results[*].name":              {"engine", "webapps"},
+		"$['report server'].url":         {"https://reports.example.com"},
+		"$[\"report server\"][\"url\"]":  {"https://reports.example.com"},
+		"$.results[*].passed":            {true, false},
+		"$.results[2].name":              nil,
+		"$.results.name":                 nil,
+		"$.missing":                      nil,
+		"$.results[0].tags[*]":           {"it"},
+		"$.*.url":                        {"https://reports.example.com"},
+		"$.results[1].duration":          {float64(3)},
+		"$.results[0]['name']":           {"engine"},
+		"$['results'][1]['tags'][0]":     nil,
+		"$.results[?(@.passed)]":         nil,
+		"$.results[0].name.first-letter": nil,
+	}
+
+	for expression, expected := range cases {
+		path, err := ParseJSONPath(expression)
+		if expression == "$.results[?(@.passed)]" {
+			if err == nil {
+				t.Errorf("Filter expressions should be rejected")
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unable to parse '%s': %s", expression, err)
+			continue
+		}
+		if values := path.Select(document); !reflect.DeepEqual(values, expected) {
+			t.Errorf("Wrong values selected by '%s'. Expected: %v, got: %v", expression, expected, values)
+		}
+	}
+}
+
+func TestParseJSONPath_Invalid(t *testing.T) {
+	for _, expression := range []string{"$..name", "$.results[0", "$['name]", "$.results[first]", "$results"} {
+		if _, err := ParseJSONPath(expression); err == nil {
+			t.Errorf("Expression '%s' should be rejected", expression)
+		}
+	}
+}
+
+func TestJSONPath_SelectString(t *testing.T) {
+	var document interface{}
+	json.Unmarshal([]byte(`{"name": "engine", "duration": 12.5, "passed": true, "tags": ["it"], "owner": null}`), &document)
+
+	cases := map[string]string{
+		"$.name":     "engine",
+		"$.duration": "12.5",
+		"$.passed":   "true",
+		"$.tags":     `["it"]`,
+	}
+	for expression, expected := range cases {
+		path, _ := ParseJSONPath(expression)
+		if value, ok := path.SelectString(document); !ok || value != expected {
+			t.Errorf("Wrong string selected by '%s'. Expected: %s, got: %s", expression, expected, value)
+		}
+	}
+
+	for _, expression := range []string{"$.owner", "$.missing"} {
+		path, _ := ParseJSONPath(expression)
+		if _, ok := path.SelectString(document); ok {
+			t.Errorf("No string should be selected by '%s'", expression)
+		}
+	}
+}
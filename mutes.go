@@ -0,0 +1,220 @@
+package dashboard
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	muteSourceConfig = "config"
+	muteSourceAPI    = "api"
+)
+
+// Mute hides the jobs matching its instance and job pattern from the broken jobs until it expires.
+// The patterns use the syntax of path.Match, except that '*' also matches '/', e.g. 'camunda-bpm-*' matches the jobs of
+// the Jenkins folder 'camunda-bpm-platform'.
+type Mute struct {
+	ID       string    `json:"id"`
+	Instance string    `json:"instance"`
+	Job      string    `json:"job"`
+	Until    time.Time `json:"until"`
+	Reason   string    `json:"reason"`
+	// Source is 'config' for mutes of the configuration file, or 'api' for mutes created at runtime.
+	Source string `json:"source"`
+}
+
+// Matches returns true, if the job of the given instance is matched by the patterns of the mute.
+func (m *Mute) Matches(instance string, job string) bool {
+	return MatchPattern(m.Instance, instance) && MatchPattern(m.Job, job)
+}
+
+// Expired returns true, if the mute is not active anymore at the given time.
+// Mutes without expiry time never expire.
+func (m *Mute) Expired(now time.Time) bool {
+	return !m.Until.IsZero() && now.After(m.Until)
+}
+
+func (m *Mute) validate() error {
+	if m.Instance == "" || m.Job == "" {
+		return errors.New("A mute requires an instance and a job pattern.")
+	}
+	if _, err := path.Match(m.Instance, ""); err != nil {
+		return fmt.Errorf("Invalid instance pattern '%s': %s", m.Instance, err)
+	}
+	if _, err := path.Match(m.Job, ""); err != nil {
+		return fmt.Errorf("Invalid job pattern '%s': %s", m.Job, err)
+	}
+	return nil
+}
+
+// MuteStore holds the mutes of the configuration file and the ones created at runtime.
+// Mutes created at runtime are persisted to a JSON file, an empty path keeps them in memory only.
+type MuteStore struct {
+	path     string
+	readOnly bool
+	mutex    sync.RWMutex
+	mutes    []*Mute
+}
+
+// NewMuteStore returns a MuteStore with the given configured mutes, backed by the file at the given path.
+func NewMuteStore(path string, configured []*Mute) (*MuteStore, error) {
+	store := &MuteStore{path: path}
+
+	for i, mute := range configured {
+		if err := mute.validate(); err != nil {
+			return nil, err
+		}
+		mute.ID = fmt.Sprintf("config-%d", i+1)
+		mute.Source = muteSourceConfig
+		store.mutes = append(store.mutes, mute)
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read mutes file: %s", err)
+	}
+
+	var mutes []*Mute
+	if err := json.Unmarshal(content, &mutes); err != nil {
+		return nil, fmt.Errorf("Error while unmarshalling mutes file '%s': %s", path, err)
+	}
+	store.mutes = append(store.mutes, mutes...)
+
+	return store, nil
+}
+
+// NewReadOnlyMuteStore returns a MuteStore with the given configured mutes and the ones persisted to the file at the
+// given path, which is never written. Mutes can't be added or removed.
+func NewReadOnlyMuteStore(path string, configured []*Mute) (*MuteStore, error) {
+	store, err := NewMuteStore(path, configured)
+	if err != nil {
+		return nil, err
+	}
+	store.readOnly = true
+	return store, nil
+}
+
+// Add mutes the jobs matching the given patterns until the given time.
+func (s *MuteStore) Add(instance string, job string, until time.Time, reason string) (*Mute, error) {
+	id, err := newMuteID()
+	if err != nil {
+		return nil, err
+	}
+
+	mute := &Mute{
+		ID:       id,
+		Instance: instance,
+		Job:      job,
+		Until:    until.UTC(),
+		Reason:   reason,
+		Source:   muteSourceAPI,
+	}
+	if err := mute.validate(); err != nil {
+		return nil, err
+	}
+	if until.IsZero() || !until.After(time.Now()) {
+		return nil, errors.New("A mute requires an expiry time in the future.")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.readOnly {
+		return nil, errors.New("Mutes are read-only.")
+	}
+	s.mutes = append(s.mutes, mute)
+	return mute, s.save()
+}
+
+// Remove deletes the mute with the given id.
+// It returns false, if there is no such mute. Mutes of the configuration file can't be removed.
+func (s *MuteStore) Remove(id string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.readOnly {
+		return false, errors.New("Mutes are read-only.")
+	}
+	for i, mute := range s.mutes {
+		if mute.ID != id {
+			continue
+		}
+		if mute.Source == muteSourceConfig {
+			return false, fmt.Errorf("Mute '%s' is part of the configuration file and can't be removed.", id)
+		}
+		s.mutes = append(s.mutes[:i], s.mutes[i+1:]...)
+		return true, s.save()
+	}
+	return false, nil
+}
+
+// All returns all active mutes.
+func (s *MuteStore) All() []*Mute {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := time.Now()
+	mutes := make([]*Mute, 0, len(s.mutes))
+	for _, mute := range s.mutes {
+		if !mute.Expired(now) {
+			mutes = append(mutes, mute)
+		}
+	}
+	return mutes
+}
+
+// Find returns the first active mute matching the job of the given instance or nil, if the job isn't muted.
+func (s *MuteStore) Find(instance string, job string) *Mute {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := time.Now()
+	for _, mute := range s.mutes {
+		if !mute.Expired(now) && mute.Matches(instance, job) {
+			return mute
+		}
+	}
+	return nil
+}
+
+// save writes all active mutes created at runtime to the file of the store. The caller must hold the lock.
+func (s *MuteStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	now := time.Now()
+	mutes := make([]*Mute, 0, len(s.mutes))
+	for _, mute := range s.mutes {
+		if mute.Source == muteSourceAPI && !mute.Expired(now) {
+			mutes = append(mutes, mute)
+		}
+	}
+	if err := writeJSONFile(s.path, mutes); err != nil {
+		return fmt.Errorf("Unable to persist mutes: %s", err)
+	}
+	return nil
+}
+
+func newMuteID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
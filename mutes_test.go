@@ -0,0 +1,144 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMute_Matches(t *testing.T) {
+	mute := &Mute{Instance: "Release", Job: "camunda-bpm-*"}
+
+	if !mute.Matches("Release", "camunda-bpm-platform") {
+		t.Error("Mute should match job by pattern.")
+	}
+	if mute.Matches("CI", "camunda-bpm-platform") {
+		t.Error("Mute must not match jobs of other instances.")
+	}
+	if mute.Matches("Release", "docs") {
+		t.Error("Mute must not match other jobs.")
+	}
+	if !mute.Matches("Release", "camunda-bpm-platform/master") {
+		t.Error("Mute should match jobs inside folders, as '*' matches '/'.")
+	}
+	if !(&Mute{Instance: "camunda", Job: "zeebe@*"}).Matches("camunda", "zeebe@release/0.23") {
+		t.Error("Mute should match branches containing '/'.")
+	}
+}
+
+func TestMute_Expired(t *testing.T) {
+	now := time.Now()
+
+	if (&Mute{}).Expired(now) {
+		t.Error("Mute without expiry time must never expire.")
+	}
+	if (&Mute{Until: now.Add(time.Hour)}).Expired(now) {
+		t.Error("Mute should be active until its expiry time.")
+	}
+	if !(&Mute{Until: now.Add(-time.Hour)}).Expired(now) {
+		t.Error("Mute should be expired after its expiry time.")
+	}
+}
+
+func TestMuteStore_AddAndRemove(t *testing.T) {
+	store, err := NewMuteStore("", []*Mute{{Instance: "CI", Job: "*", Reason: "migration"}})
+	assertNoError(err, t, "mute store")
+
+	mute, err := store.Add("Release", "camunda-bpm-*", time.Now().Add(time.Hour), "upstream outage")
+	assertNoError(err, t, "mute")
+
+	if found := store.Find("Release", "camunda-bpm-platform"); found != mute {
+		t.Fatalf("Expected mute %+v to be found, got %+v", mute, found)
+	}
+	if found := store.Find("CI", "anything"); found == nil || found.Source != muteSourceConfig {
+		t.Fatalf("Expected configured mute to be found, got %+v", found)
+	}
+	if len(store.All()) != 2 {
+		t.Fatalf("Wrong number of mutes. Expected 2, got %d", len(store.All()))
+	}
+
+	removed, err := store.Remove(mute.ID)
+	assertNoError(err, t, "unmute")
+	if !removed || store.Find("Release", "camunda-bpm-platform") != nil {
+		t.Fatal("Mute should have been removed.")
+	}
+
+	if _, err := store.Remove("config-1"); err == nil {
+		t.Fatal("Expecting an error when removing a configured mute, got nil")
+	}
+}
+
+func TestMuteStore_AddValidatesMute(t *testing.T) {
+	store, _ := NewMuteStore("", nil)
+
+	if _, err := store.Add("Release", "job", time.Time{}, ""); err == nil {
+		t.Error("Expecting an error for a mute without expiry time, got nil")
+	}
+	if _, err := store.Add("Release", "job", time.Now().Add(-time.Hour), ""); err == nil {
+		t.Error("Expecting an error for an expired mute, got nil")
+	}
+	if _, err := store.Add("Release", "[", time.Now().Add(time.Hour), ""); err == nil {
+		t.Error("Expecting an error for an invalid pattern, got nil")
+	}
+}
+
+func TestMuteStore_IgnoresExpiredMutes(t *testing.T) {
+	store, _ := NewMuteStore("", []*Mute{{Instance: "*", Job: "*", Until: time.Now().Add(-time.Minute)}})
+
+	if store.Find("Release", "job") != nil {
+		t.Error("Expired mute must not match.")
+	}
+	if len(store.All()) != 0 {
+		t.Error("Expired mutes must not be returned.")
+	}
+}
+
+func TestMuteStore_PersistsMutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mutes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mutes.json")
+
+	configured := []*Mute{{Instance: "CI", Job: "*"}}
+	store, _ := NewMuteStore(path, configured)
+	store.Add("Release", "camunda-bpm-*", time.Now().Add(time.Hour), "upstream outage")
+
+	reloaded, err := NewMuteStore(path, configured)
+	assertNoError(err, t, "reloaded mute store")
+
+	mutes := reloaded.All()
+	if len(mutes) != 2 {
+		t.Fatalf("Wrong number of mutes loaded. Expected 2, got %d", len(mutes))
+	}
+	if mutes[1].Job != "camunda-bpm-*" || mutes[1].Reason != "upstream outage" {
+		t.Fatalf("Wrong mute loaded: %+v", mutes[1])
+	}
+}
+
+func TestMuteStore_ReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mutes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mutes.json")
+
+	store, _ := NewMuteStore(path, nil)
+	mute, _ := store.Add("Release", "camunda-bpm-*", time.Now().Add(time.Hour), "upstream outage")
+
+	readOnly, err := NewReadOnlyMuteStore(path, nil)
+	assertNoError(err, t, "read-only mute store")
+	if readOnly.Find("Release", "camunda-bpm-platform") == nil {
+		t.Error("Read-only store should load the persisted mutes")
+	}
+	if _, err := readOnly.Add("Release", "docs", time.Now().Add(time.Hour), ""); err == nil || len(readOnly.All()) != 1 {
+		t.Errorf("Read-only store should reject new mutes, got %v", err)
+	}
+	if removed, err := readOnly.Remove(mute.ID); err == nil || removed || len(readOnly.All()) != 1 {
+		t.Errorf("Read-only store should reject removals, got %v", err)
+	}
+}
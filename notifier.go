@@ -0,0 +1,308 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// The types of the events sent to the sinks.
+const (
+	// EventBroken is sent when a job breaks.
+	EventBroken = "broken"
+	// EventFixed is sent when a broken job succeeds again.
+	EventFixed = "fixed"
+	// EventDown is sent when an instance isn't available anymore.
+	EventDown = "down"
+	// EventUp is sent when an instance is available again.
+	EventUp = "up"
+	// EventDigest is sent by the scheduled digests, regardless of the filters of the sinks.
+	EventDigest = "digest"
+)
+
+const (
+	// DefaultSinkRetries is the number of retries of a failed notification, if no retries are configured.
+	DefaultSinkRetries = 3
+	// DefaultSinkRetryDelay is the delay before the first retry, which doubles with every further retry.
+	DefaultSinkRetryDelay = 10 * time.Second
+
+	// sinkQueueSize is the number of events a sink buffers, further events are dropped.
+	sinkQueueSize = 100
+
+	defaultMessageTemplate = `{{if .Job}}{{.Job.Name}} on {{.Instance}} {{if eq .Type "broken"}}is broken{{else}}is fixed{{end}}` +
+		`{{if .Job.URL}}: {{.Job.URL}}{{end}}` +
+		`{{else}}{{.Instance}} {{if eq .Type "down"}}is {{.Health.State}}{{if .Health.Error}}: {{.Health.Error}}{{end}}` +
+		`{{else}}is up again{{end}}{{end}}`
+)
+
+// Event is a transition of a job or an instance.
+type Event struct {
+	// Type is one of EventBroken, EventFixed, EventDown, EventUp and EventDigest.
+	Type         string `json:"type"`
+	Instance     string `json:"instance"`
+	InstanceType string `json:"instanceType"`
+	InstanceUrl  string `json:"instanceUrl,omitempty"`
+	// Job is the job which broke or got fixed, it is nil for transitions of the instance.
+	Job *Job `json:"job,omitempty"`
+	// Health is the health of the instance, which is set for transitions of the instance only.
+	Health *Health `json:"health,omitempty"`
+	// Digest is the digest of digest events, which don't concern a single instance.
+	Digest *Digest   `json:"digest,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// Notifier sends events to a chat, another service or by mail.
+type Notifier interface {
+	// Notify sends the event along with the message rendered from the template of its sink.
+	Notify(event Event, message string) error
+}
+
+// NotifierConfig is the configuration of a single sink, as found in the config file.
+type NotifierConfig map[string]interface{}
+
+// Decode stores the configuration in the value pointed to by v, like ProviderConfig.Decode.
+func (c NotifierConfig) Decode(v interface{}) error {
+	return ProviderConfig(c).Decode(v)
+}
+
+// NotifierFactory creates a Notifier from its configuration.
+type NotifierFactory func(config NotifierConfig) (Notifier, error)
+
+var (
+	notifierFactoriesMutex sync.RWMutex
+	notifierFactories      = make(map[string]NotifierFactory)
+)
+
+// RegisterNotifier makes the notifiers of the given type available to NewSink.
+// It panics, if a factory is registered twice for the same type.
+func RegisterNotifier(notifierType string, factory NotifierFactory) {
+	notifierFactoriesMutex.Lock()
+	defer notifierFactoriesMutex.Unlock()
+
+	if _, ok := notifierFactories[notifierType]; ok {
+		panic(fmt.Sprintf("Notifier type '%s' is registered twice", notifierType))
+	}
+	notifierFactories[notifierType] = factory
+}
+
+// NotifierTypes returns the sorted types of all registered notifiers.
+func NotifierTypes() []string {
+	notifierFactoriesMutex.RLock()
+	defer notifierFactoriesMutex.RUnlock()
+
+	types := make([]string, 0, len(notifierFactories))
+	for notifierType := range notifierFactories {
+		types = append(types, notifierType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Sink routes the events matching its filters to a Notifier. Failed notifications are retried with a delay,
+// which doubles with every retry.
+type Sink struct {
+	Name     string
+	Notifier Notifier
+	Template *template.Template
+	// Events are the types of the events sent to the sink, all events are sent if empty.
+	Events []string
+	// Instances and Jobs are patterns in the syntax of path.Match, whose '*' also matches '/'. They match all instances
+	// respectively jobs if empty.
+	// Events of instances are sent regardless of the job patterns.
+	Instances []string
+	Jobs      []string
+	// Teams are the names of the teams whose jobs are sent, all jobs are sent if empty. Like the job patterns, they
+	// don't apply to events of instances.
+	Teams      []string
+	Retries    int
+	RetryDelay time.Duration
+
+	startOnce sync.Once
+	queue     chan Event
+}
+
+// NewSink creates the Sink with the given name from the config keys 'template', 'events', 'instances', 'jobs',
+// 'teams', 'retries' and 'retryDelay'. The Notifier is created by the factory registered for the given type.
+func NewSink(notifierType string, name string, config NotifierConfig) (*Sink, error) {
+	var cfg struct {
+		Template   string
+		Events     []string
+		Instances  []string
+		Jobs       []string
+		Teams      []string
+		Retries    *int
+		RetryDelay string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("Sink '%s' is misconfigured: %s", name, err)
+	}
+
+	notifierFactoriesMutex.RLock()
+	factory, ok := notifierFactories[notifierType]
+	notifierFactoriesMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Sink '%s' has the unknown type '%s', known types are %v.", name, notifierType, NotifierTypes())
+	}
+
+	notifier, err := factory(config)
+	if err != nil {
+		return nil, fmt.Errorf("Sink '%s' of type '%s' is misconfigured: %s", name, notifierType, err)
+	}
+
+	sink := &Sink{
+		Name:       name,
+		Notifier:   notifier,
+		Events:     cfg.Events,
+		Instances:  cfg.Instances,
+		Jobs:       cfg.Jobs,
+		Teams:      cfg.Teams,
+		Retries:    DefaultSinkRetries,
+		RetryDelay: DefaultSinkRetryDelay,
+	}
+	if sink.Template, err = newMessageTemplate(name, cfg.Template); err != nil {
+		return nil, fmt.Errorf("Sink '%s' has an invalid template: %s", name, err)
+	}
+	if cfg.Retries != nil {
+		sink.Retries = *cfg.Retries
+	}
+	if cfg.RetryDelay != "" {
+		if sink.RetryDelay, err = time.ParseDuration(cfg.RetryDelay); err != nil {
+			return nil, fmt.Errorf("Sink '%s' has an invalid retry delay: %s", name, err)
+		}
+	}
+	return sink, nil
+}
+
+// newMessageTemplate parses the given template of the messages about events, or the default one if it is empty.
+func newMessageTemplate(name string, text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultMessageTemplate
+	}
+	return template.New(name).Option("missingkey=zero").Parse(text)
+}
+
+// Validate checks that the sink has a notifier and its patterns are valid.
+func (s *Sink) Validate() error {
+	if s.Notifier == nil || s.Template == nil {
+		return fmt.Errorf("Sink '%s' has no notifier or template.", s.Name)
+	}
+	if s.Retries < 0 {
+		return fmt.Errorf("Sink '%s' has a negative number of retries.", s.Name)
+	}
+	for _, eventType := range s.Events {
+		switch eventType {
+		case EventBroken, EventFixed, EventDown, EventUp:
+		default:
+			return fmt.Errorf("Sink '%s' has the unknown event '%s'.", s.Name, eventType)
+		}
+	}
+	for _, pattern := range append(append([]string{}, s.Instances...), s.Jobs...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("Sink '%s' has an invalid pattern '%s': %s", s.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// Matches returns true, if the event passes the filters of the sink.
+func (s *Sink) Matches(event Event) bool {
+	if len(s.Events) > 0 && !containsString(s.Events, event.Type) {
+		return false
+	}
+	if len(s.Instances) > 0 && !matchesAny(s.Instances, event.Instance) {
+		return false
+	}
+	if event.Job == nil {
+		return true
+	}
+	if len(s.Teams) > 0 && (event.Job.Owner == nil || !containsString(s.Teams, event.Job.Owner.Name)) {
+		return false
+	}
+	return len(s.Jobs) == 0 || matchesAny(s.Jobs, event.Job.ID)
+}
+
+// ownerOf returns the team owning the job of the event, or nil for events of instances and jobs without owner.
+func ownerOf(event Event) *Team {
+	if event.Job == nil {
+		return nil
+	}
+	return event.Job.Owner
+}
+
+// Send renders the message of the event and passes it to the notifier, retrying failed attempts.
+func (s *Sink) Send(event Event) error {
+	var message bytes.Buffer
+	if err := s.Template.Execute(&message, event); err != nil {
+		return fmt.Errorf("Unable to render message of sink '%s': %s", s.Name, err)
+	}
+	return s.Deliver(event, message.String())
+}
+
+// Deliver passes the event along with the given message to the notifier, retrying failed attempts.
+func (s *Sink) Deliver(event Event, message string) error {
+	delay := s.RetryDelay
+	err := s.Notifier.Notify(event, message)
+	for retry := 1; err != nil && retry <= s.Retries; retry++ {
+		log.Printf("[WARN] Sink '%s' failed to send %s event of %s, retrying in %s: %s", s.Name, event.Type, event.Instance, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+		err = s.Notifier.Notify(event, message)
+	}
+	if err != nil {
+		return fmt.Errorf("Sink '%s' failed to send %s event of %s: %s", s.Name, event.Type, event.Instance, err)
+	}
+	return nil
+}
+
+// enqueue passes the event to the background sender of the sink. Events are dropped while the queue is full,
+// so a failing sink doesn't block the dashboard.
+func (s *Sink) enqueue(event Event) {
+	s.startOnce.Do(func() {
+		s.queue = make(chan Event, sinkQueueSize)
+		go func() {
+			for event := range s.queue {
+				if err := s.Send(event); err != nil {
+					log.Printf("[WARN] %s", err)
+				}
+			}
+		}()
+	})
+
+	select {
+	case s.queue <- event:
+	default:
+		log.Printf("[WARN] Sink '%s' is congested, dropped %s event of %s.", s.Name, event.Type, event.Instance)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPattern returns true, if the name matches the pattern in the syntax of path.Match. Unlike path.Match, '*'
+// and '?' also match '/', so that 'camunda-*' matches jobs inside Jenkins folders and branches like 'release/7.x'.
+// Invalid patterns never match.
+func MatchPattern(pattern string, name string) bool {
+	matches, _ := path.Match(strings.Replace(pattern, "/", "\x00", -1), strings.Replace(name, "/", "\x00", -1))
+	return matches
+}
@@ -0,0 +1,66 @@
+package dashboard
+
+import (
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+)
+
+// SlackNotifier posts the messages of events to an incoming webhook of Slack or Mattermost.
+type SlackNotifier struct {
+	Url string
+	// Channel, Username and IconEmoji override the defaults of the incoming webhook, if set. Messages about jobs of a
+	// team with a channel are posted to the channel of the team instead.
+	Channel   string
+	Username  string
+	IconEmoji string
+	Client    *client.HTTPClient
+}
+
+type slackMessage struct {
+	Text      string `json:"text"`
+	Channel   string `json:"channel,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IconEmoji string `json:"icon_emoji,omitempty"`
+}
+
+func init() {
+	RegisterNotifier("slack", newSlackNotifier)
+}
+
+// newSlackNotifier creates a SlackNotifier from the config keys 'url', 'channel', 'username' and 'iconEmoji'.
+func newSlackNotifier(config NotifierConfig) (Notifier, error) {
+	var cfg struct {
+		Url       string
+		Channel   string
+		Username  string
+		IconEmoji string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newNotifierClient(cfg.Url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SlackNotifier{
+		Url:       cfg.Url,
+		Channel:   cfg.Channel,
+		Username:  cfg.Username,
+		IconEmoji: cfg.IconEmoji,
+		Client:    httpClient,
+	}, nil
+}
+
+func (s *SlackNotifier) Notify(event Event, message string) error {
+	channel := s.Channel
+	if owner := ownerOf(event); owner != nil && owner.Channel != "" {
+		channel = owner.Channel
+	}
+
+	return postNotification(s.Client, s.Url, slackMessage{
+		Text:      message,
+		Channel:   channel,
+		Username:  s.Username,
+		IconEmoji: s.IconEmoji,
+	})
+}
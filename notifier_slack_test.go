@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSlackNotifier_Notify(t *testing.T) {
+	var message slackMessage
+	server := mockServer(http.StatusOK, "text/plain", "ok", func(r *http.Request) {
+		if r.URL.Path != "/hooks/T0/B0/X" {
+			t.Errorf("Wrong path requested, got %s", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &message)
+	})
+	defer server.Close()
+
+	notifier, err := newSlackNotifier(NotifierConfig{"url": server.URL + "/hooks/T0/B0/X", "channel": "#ci", "iconEmoji": ":rotating_light:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := notifier.Notify(Event{Type: EventDown, Instance: "Release"}, "Release is down"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := slackMessage{Text: "Release is down", Channel: "#ci", IconEmoji: ":rotating_light:"}
+	if !reflect.DeepEqual(message, expected) {
+		t.Fatalf("Wrong message posted. Expected: %+v, got: %+v", expected, message)
+	}
+
+	event := Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "docs", Owner: &Team{Name: "docs", Channel: "#docs"}}}
+	if err := notifier.Notify(event, "docs on Release is broken"); err != nil {
+		t.Fatal(err)
+	}
+	if message.Channel != "#docs" {
+		t.Fatalf("Message should be posted to the channel of the owning team, got %+v", message)
+	}
+}
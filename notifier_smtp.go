@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultSMTPPort is the port of the mail server, if no port is configured.
+	DefaultSMTPPort = 25
+
+	defaultSubjectTemplate = `{{if .Digest}}CI digest {{.Digest.Name}}{{else}}[{{.Instance}}] ` +
+		`{{if .Job}}{{.Job.Name}} is {{.Type}}{{else}}Instance is {{.Type}}{{end}}{{end}}`
+)
+
+// SMTPNotifier sends the messages of events by mail.
+type SMTPNotifier struct {
+	// Addr is the 'host:port' of the mail server.
+	Addr string
+	// Auth authenticates with the mail server, nil sends mails without authentication.
+	Auth    smtp.Auth
+	From    string
+	To      []string
+	Subject *template.Template
+}
+
+func init() {
+	RegisterNotifier("smtp", newSMTPNotifier)
+}
+
+// newSMTPNotifier creates a SMTPNotifier from the config keys 'host', 'port', 'username', 'password', 'from', 'to'
+// and 'subject'. The credentials are only sent over TLS, except to localhost.
+func newSMTPNotifier(config NotifierConfig) (Notifier, error) {
+	var cfg struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+		To       []string
+		Subject  string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("host, from or to are not configured")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = DefaultSMTPPort
+	}
+
+	notifier := &SMTPNotifier{Addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)), From: cfg.From, To: cfg.To}
+	if cfg.Username != "" {
+		notifier.Auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	subject := cfg.Subject
+	if subject == "" {
+		subject = defaultSubjectTemplate
+	}
+	var err error
+	if notifier.Subject, err = template.New("subject").Option("missingkey=zero").Parse(subject); err != nil {
+		return nil, fmt.Errorf("Invalid subject: %s", err)
+	}
+	return notifier, nil
+}
+
+// Notify sends the message as plain text mail to all recipients, as well as to the contact of the team owning the
+// job. Digests are sent as HTML mail instead.
+func (s *SMTPNotifier) Notify(event Event, message string) error {
+	var subject bytes.Buffer
+	if err := s.Subject.Execute(&subject, event); err != nil {
+		return fmt.Errorf("Unable to render subject: %s", err)
+	}
+
+	contentType := "text/plain"
+	if event.Digest != nil {
+		html, err := event.Digest.HTML()
+		if err != nil {
+			return err
+		}
+		contentType, message = "text/html", html
+	}
+
+	to := s.To
+	if owner := ownerOf(event); owner != nil && owner.Contact != "" && !containsString(to, owner.Contact) {
+		to = append(append([]string{}, to...), owner.Contact)
+	}
+
+	var mail bytes.Buffer
+	fmt.Fprintf(&mail, "From: %s\r\n", s.From)
+	fmt.Fprintf(&mail, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&mail, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&mail, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	mail.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&mail, "Content-Type: %s; charset=utf-8\r\n\r\n", contentType)
+	mail.WriteString(strings.Replace(message, "\n", "\r\n", -1))
+	mail.WriteString("\r\n")
+
+	return smtp.SendMail(s.Addr, s.Auth, s.From, to, mail.Bytes())
+}
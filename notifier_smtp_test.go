@@ -0,0 +1,115 @@
+package dashboard
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	server := newTestSMTPServer(t)
+	defer server.Close()
+
+	host, port, _ := net.SplitHostPort(server.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	notifier, err := newSMTPNotifier(NotifierConfig{
+		"host": host,
+		"port": portNumber,
+		"from": "ci@example.com",
+		"to":   []interface{}{"jane@example.com", "john@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := &Team{Name: "docs", Contact: "docs@example.com"}
+	event := Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "docs", Name: "docs", Owner: owner}, Time: time.Now()}
+	if err := notifier.Notify(event, "docs on Release is broken"); err != nil {
+		t.Fatal(err)
+	}
+
+	mail := <-server.mails
+	for _, recipient := range []string{"jane@example.com", "john@example.com", "docs@example.com"} {
+		if !strings.Contains(mail, "RCPT TO:<"+recipient+">") {
+			t.Fatalf("Mail should be sent to all recipients and the owning team, got %s", mail)
+		}
+	}
+	if !strings.Contains(mail, "Subject: [Release] docs is broken") || !strings.Contains(mail, "\r\n\r\ndocs on Release is broken\r\n") {
+		t.Fatalf("Mail should contain the subject and message, got %s", mail)
+	}
+}
+
+func TestSMTPNotifier_NotifyFails(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	notifier := &SMTPNotifier{Addr: addr, From: "ci@example.com", To: []string{"jane@example.com"}}
+	notifier.Subject, _ = newMessageTemplate("subject", defaultSubjectTemplate)
+
+	if err := notifier.Notify(Event{Type: EventUp, Instance: "Release"}, "Release is up again"); err == nil {
+		t.Fatal("Unreachable mail server should be reported")
+	}
+}
+
+// testSMTPServer is a minimal mail server accepting every mail, which passes the whole conversation to mails.
+type testSMTPServer struct {
+	net.Listener
+	mails chan string
+}
+
+func newTestSMTPServer(t *testing.T) *testSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &testSMTPServer{Listener: listener, mails: make(chan string, 1)}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var conversation strings.Builder
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		data := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			conversation.WriteString(line)
+
+			switch {
+			case data && line == ".\r\n":
+				data = false
+				reply("250 OK")
+			case data:
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "DATA"):
+				data = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 Bye")
+				server.mails <- conversation.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return server
+}
@@ -0,0 +1,177 @@
+package dashboard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink("webhook", "Alerts", NotifierConfig{
+		"url":        "https://alerts.example.com/hooks/ci",
+		"instances":  []interface{}{"Release"},
+		"retries":    0,
+		"retryDelay": "1s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := sink.Notifier.(*WebhookNotifier); !ok {
+		t.Fatalf("Wrong notifier created, got %T", sink.Notifier)
+	}
+	if sink.Retries != 0 || sink.RetryDelay != time.Second || len(sink.Instances) != 1 {
+		t.Fatalf("Sink should be configured, got %+v", sink)
+	}
+	if err := sink.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	sink, _ = NewSink("slack", "Chat", NotifierConfig{"url": "https://hooks.slack.com/services/T0/B0/X"})
+	if sink.Retries != DefaultSinkRetries || sink.RetryDelay != DefaultSinkRetryDelay {
+		t.Fatalf("Sink should use the default retries, got %+v", sink)
+	}
+}
+
+func TestNewSink_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		notifierType string
+		config       NotifierConfig
+	}{
+		"unknown type":        {"pager", NotifierConfig{}},
+		"invalid URL":         {"slack", NotifierConfig{"url": "hooks.slack.com"}},
+		"invalid template":    {"slack", NotifierConfig{"url": "https://hooks.slack.com", "template": "{{.Job"}},
+		"invalid retry delay": {"webhook", NotifierConfig{"url": "https://alerts.example.com", "retryDelay": "soon"}},
+		"missing recipients":  {"smtp", NotifierConfig{"host": "mail.example.com", "from": "ci@example.com"}},
+	}
+	for name, test := range tests {
+		if _, err := NewSink(test.notifierType, "Alerts", test.config); err == nil || !strings.Contains(err.Error(), "'Alerts'") {
+			t.Errorf("Sink with %s should be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestSink_Validate(t *testing.T) {
+	tests := map[string]*Sink{
+		"unknown event":    {Events: []string{"flaky"}},
+		"invalid pattern":  {Jobs: []string{"camunda-["}},
+		"negative retries": {Retries: -1},
+	}
+	for name, sink := range tests {
+		sink.Name = "Alerts"
+		sink.Notifier = &TestNotifier{}
+		sink.Template, _ = newMessageTemplate("Alerts", "")
+		if err := sink.Validate(); err == nil {
+			t.Errorf("Sink with %s should be rejected", name)
+		}
+	}
+}
+
+func TestSink_Matches(t *testing.T) {
+	sink := &Sink{Events: []string{EventBroken, EventDown}, Instances: []string{"Release", "Docs*"}, Jobs: []string{"camunda-bpm-*"}}
+
+	tests := []struct {
+		event   Event
+		matches bool
+	}{
+		{Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "camunda-bpm-platform"}}, true},
+		{Event{Type: EventBroken, Instance: "Docs-Preview", Job: &Job{ID: "camunda-bpm-docs"}}, true},
+		{Event{Type: EventDown, Instance: "Release"}, true},
+		{Event{Type: EventFixed, Instance: "Release", Job: &Job{ID: "camunda-bpm-platform"}}, false},
+		{Event{Type: EventBroken, Instance: "CI", Job: &Job{ID: "camunda-bpm-platform"}}, false},
+		{Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "zeebe"}}, false},
+	}
+	for i, test := range tests {
+		if matches := sink.Matches(test.event); matches != test.matches {
+			t.Errorf("Wrong match of event #%d. Expected: %t, got: %t", i, test.matches, matches)
+		}
+	}
+
+	if !(&Sink{}).Matches(Event{Type: EventUp, Instance: "Release"}) {
+		t.Error("Sink without filters should match all events")
+	}
+
+	team := &Sink{Teams: []string{"optimize"}}
+	if !team.Matches(Event{Type: EventBroken, Job: &Job{ID: "docs", Owner: &Team{Name: "optimize"}}}) ||
+		team.Matches(Event{Type: EventBroken, Job: &Job{ID: "docs", Owner: &Team{Name: "platform"}}}) ||
+		team.Matches(Event{Type: EventBroken, Job: &Job{ID: "docs"}}) || !team.Matches(Event{Type: EventDown}) {
+		t.Error("Sink should only match the jobs of its teams and all events of instances")
+	}
+}
+
+func TestSink_Send(t *testing.T) {
+	notifier := &TestNotifier{failures: 2}
+	sink := &Sink{Name: "Alerts", Notifier: notifier, Retries: 2, RetryDelay: time.Millisecond}
+	sink.Template, _ = newMessageTemplate("Alerts", "{{.Instance}}: {{.Job.ID}} {{.Type}}")
+
+	err := sink.Send(Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "camunda-bpm-platform"}})
+
+	if err != nil || len(notifier.messages) != 1 || notifier.messages[0] != "Release: camunda-bpm-platform broken" {
+		t.Fatalf("Message should be sent after retries, got %v, %v", notifier.messages, err)
+	}
+
+	notifier = &TestNotifier{failures: 3}
+	sink.Notifier = notifier
+	if err := sink.Send(Event{Type: EventFixed, Instance: "Release", Job: &Job{ID: "docs"}}); err == nil || notifier.attempts != 3 {
+		t.Fatalf("Sink should give up after the retries, got %d attempts, %v", notifier.attempts, err)
+	}
+}
+
+func TestDefaultMessageTemplate(t *testing.T) {
+	tests := []struct {
+		event    Event
+		expected string
+	}{
+		{
+			Event{Type: EventBroken, Instance: "Release", Job: &Job{Name: "camunda-bpm-platform", URL: "https://ci.example.com/job/camunda-bpm-platform/"}},
+			"camunda-bpm-platform on Release is broken: https://ci.example.com/job/camunda-bpm-platform/",
+		},
+		{Event{Type: EventFixed, Instance: "camunda", Job: &Job{Name: "zeebe » master"}}, "zeebe » master on camunda is fixed"},
+		{Event{Type: EventDown, Instance: "Release", Health: &Health{State: HealthUnauthorized, Error: "Authentication required."}}, "Release is unauthorized: Authentication required."},
+		{Event{Type: EventUp, Instance: "Release", Health: &Health{State: HealthOk}}, "Release is up again"},
+	}
+
+	tmpl, err := newMessageTemplate("default", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		var message strings.Builder
+		if err := tmpl.Execute(&message, test.event); err != nil {
+			t.Fatal(err)
+		}
+		if message.String() != test.expected {
+			t.Errorf("Wrong message rendered. Expected: %s, got: %s", test.expected, message.String())
+		}
+	}
+}
+
+func TestNotifierTypes(t *testing.T) {
+	if types := strings.Join(NotifierTypes(), ","); types != "slack,smtp,webhook" {
+		t.Fatalf("Wrong notifier types registered, got %s", types)
+	}
+}
+
+/**
+ * Test implementation of Notifier
+ */
+
+type TestNotifier struct {
+	failures int
+	attempts int
+	messages []string
+	events   chan Event
+}
+
+func (n *TestNotifier) Notify(event Event, message string) error {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return errors.New("service unavailable")
+	}
+	n.messages = append(n.messages, message)
+	if n.events != nil {
+		n.events <- event
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package dashboard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	client "github.com/camunda-ci/camunda-ci-dashboard/http"
+	"net/url"
+)
+
+// WebhookNotifier posts every event as JSON to a URL, along with its message.
+type WebhookNotifier struct {
+	Url    string
+	Client *client.HTTPClient
+}
+
+func init() {
+	RegisterNotifier("webhook", newWebhookNotifier)
+}
+
+// newWebhookNotifier creates a WebhookNotifier from the config keys 'url' and 'headers'.
+func newWebhookNotifier(config NotifierConfig) (Notifier, error) {
+	var cfg struct {
+		Url     string
+		Headers map[string]string
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newNotifierClient(cfg.Url, cfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookNotifier{Url: cfg.Url, Client: httpClient}, nil
+}
+
+// Notify posts the event with an additional 'message' attribute.
+func (w *WebhookNotifier) Notify(event Event, message string) error {
+	payload := struct {
+		Event
+		Message string `json:"message"`
+	}{event, message}
+	return postNotification(w.Client, w.Url, payload)
+}
+
+// newNotifierClient returns a client for posting notifications to the given URL, sending the given headers.
+func newNotifierClient(rawUrl string, headers map[string]string) (*client.HTTPClient, error) {
+	if err := validateUrl(rawUrl); err != nil {
+		return nil, fmt.Errorf("Invalid URL: %s", err)
+	}
+
+	// the URL is split into the base URL of the client and the path posted to
+	u, _ := url.Parse(rawUrl)
+	httpConfig := client.NewHTTPConfig(u.Scheme+"://"+u.Host, "", "", "")
+	for header, value := range headers {
+		httpConfig.SetHeader(header, value)
+	}
+	return client.NewHTTPClient(httpConfig), nil
+}
+
+// postNotification posts the payload as JSON to the URL of the client.
+func postNotification(httpClient *client.HTTPClient, rawUrl string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return err
+	}
+
+	response, err := httpClient.PostTo(u.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
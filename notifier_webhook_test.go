@@ -0,0 +1,52 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var payload map[string]interface{}
+	server := mockServer(http.StatusNoContent, "", "", func(r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.RequestURI() != "/hooks/ci?source=dashboard" || r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("Wrong request, got %s %s", r.Method, r.URL.RequestURI())
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("Payload should be JSON, got %s", body)
+		}
+	})
+	defer server.Close()
+
+	notifier, err := newWebhookNotifier(NotifierConfig{
+		"url":     server.URL + "/hooks/ci?source=dashboard",
+		"headers": map[string]interface{}{"X-Api-Key": "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := Event{Type: EventBroken, Instance: "Release", Job: &Job{ID: "docs", Color: "red"}, Time: time.Now()}
+	if err := notifier.Notify(event, "docs on Release is broken"); err != nil {
+		t.Fatal(err)
+	}
+
+	job, _ := payload["job"].(map[string]interface{})
+	if payload["type"] != "broken" || payload["message"] != "docs on Release is broken" || job["id"] != "docs" {
+		t.Fatalf("Event should be posted with its message, got %+v", payload)
+	}
+}
+
+func TestWebhookNotifier_NotifyFails(t *testing.T) {
+	server := mockServer(http.StatusBadGateway, "text/plain", "upstream unavailable", nil)
+	defer server.Close()
+
+	notifier, _ := newWebhookNotifier(NotifierConfig{"url": server.URL})
+
+	if err := notifier.Notify(Event{Type: EventUp, Instance: "Release"}, "Release is up again"); err == nil {
+		t.Fatal("Failed request should be reported")
+	}
+}
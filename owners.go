@@ -0,0 +1,121 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Team owns jobs and is notified about them.
+type Team struct {
+	Name string `json:"name"`
+	// Contact is the mail address of the team, which also receives the mails of smtp sinks about its jobs.
+	Contact string `json:"contact,omitempty"`
+	// Channel is the chat channel of the team, which receives the messages of slack sinks about its jobs.
+	Channel string `json:"channel,omitempty"`
+}
+
+// OwnershipRule assigns the jobs matching its patterns to a team. The instance and job patterns use the syntax of
+// path.Match, except that '*' also matches '/'. Alternatively the job is matched by the regular expression Regex.
+type OwnershipRule struct {
+	Team     string `json:"team"`
+	Instance string `json:"instance"`
+	Job      string `json:"job"`
+	Regex    string `json:"regex"`
+
+	regex *regexp.Regexp
+}
+
+// Matches returns true, if the job of the given instance is matched by the patterns of the rule.
+// Rules without instance pattern match all instances.
+func (r *OwnershipRule) Matches(instance string, job string) bool {
+	if r.Instance != "" && !MatchPattern(r.Instance, instance) {
+		return false
+	}
+	if r.regex != nil {
+		return r.regex.MatchString(job)
+	}
+	return MatchPattern(r.Job, job)
+}
+
+func (r *OwnershipRule) validate() error {
+	if r.Team == "" {
+		return errors.New("An ownership rule requires a team.")
+	}
+	if (r.Job == "") == (r.Regex == "") {
+		return fmt.Errorf("Ownership rule of team '%s' requires either a job pattern or a regex.", r.Team)
+	}
+	if _, err := path.Match(r.Instance, ""); err != nil {
+		return fmt.Errorf("Invalid instance pattern '%s': %s", r.Instance, err)
+	}
+	if _, err := path.Match(r.Job, ""); err != nil {
+		return fmt.Errorf("Invalid job pattern '%s': %s", r.Job, err)
+	}
+	if r.Regex != "" {
+		regex, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("Invalid job regex '%s': %s", r.Regex, err)
+		}
+		r.regex = regex
+	}
+	return nil
+}
+
+// Ownership maps jobs to the teams owning them. The first matching rule determines the owner of a job.
+type Ownership struct {
+	teams map[string]*Team
+	rules []*OwnershipRule
+}
+
+// NewOwnership returns the Ownership of the given teams and rules. Every rule has to reference one of the teams.
+func NewOwnership(teams []*Team, rules []*OwnershipRule) (*Ownership, error) {
+	ownership := &Ownership{teams: make(map[string]*Team, len(teams)), rules: rules}
+	for _, team := range teams {
+		if team.Name == "" {
+			return nil, errors.New("A team requires a name.")
+		}
+		if _, ok := ownership.teams[team.Name]; ok {
+			return nil, fmt.Errorf("Team '%s' is configured twice.", team.Name)
+		}
+		ownership.teams[team.Name] = team
+	}
+
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := ownership.teams[rule.Team]; !ok {
+			return nil, fmt.Errorf("Ownership rule references the unknown team '%s'.", rule.Team)
+		}
+	}
+	return ownership, nil
+}
+
+// OwnerOf returns the team owning the job of the given instance, or nil if no rule matches.
+func (o *Ownership) OwnerOf(instance string, job string) *Team {
+	for _, rule := range o.rules {
+		if rule.Matches(instance, job) {
+			return o.teams[rule.Team]
+		}
+	}
+	return nil
+}
+
+// UseOwnership attaches the team owning them to the jobs.
+func (d *Dashboard) UseOwnership(ownership *Ownership) {
+	d.ownership = ownership
+}
+
+// applyOwners attaches the owning team to the jobs and muted jobs of the aggregation.
+func (d *Dashboard) applyOwners(aggregation *InstanceAggregation) {
+	if d.ownership == nil {
+		return
+	}
+
+	for _, jobs := range [][]Job{aggregation.Jobs, aggregation.Muted} {
+		for i := range jobs {
+			jobs[i].Owner = d.ownership.OwnerOf(aggregation.Name, jobs[i].ID)
+		}
+	}
+}
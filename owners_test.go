@@ -0,0 +1,87 @@
+package dashboard
+
+import (
+	"testing"
+)
+
+func TestOwnership_OwnerOf(t *testing.T) {
+	optimize := &Team{Name: "optimize", Channel: "#optimize"}
+	platform := &Team{Name: "platform", Contact: "platform@example.com"}
+	ownership, err := NewOwnership([]*Team{optimize, platform}, []*OwnershipRule{
+		{Team: "optimize", Instance: "Release", Job: "camunda-optimize-*"},
+		{Team: "optimize", Regex: "^optimize-(ci|release)$"},
+		{Team: "platform", Job: "camunda-*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		instance string
+		job      string
+		owner    *Team
+	}{
+		{"Release", "camunda-optimize-docs", optimize},
+		{"CI", "camunda-optimize-docs", platform},
+		{"CI", "optimize-release", optimize},
+		{"CI", "optimize-release-notes", nil},
+		{"CI", "camunda-bpm-platform", platform},
+		{"CI", "zeebe", nil},
+		{"Release", "camunda-optimize-folder/master", optimize},
+		{"CI", "camunda-bpm-platform@release/7.13", platform},
+	}
+	for _, test := range tests {
+		if owner := ownership.OwnerOf(test.instance, test.job); owner != test.owner {
+			t.Errorf("Wrong owner of '%s/%s'. Expected: %v, got: %v", test.instance, test.job, test.owner, owner)
+		}
+	}
+}
+
+func TestNewOwnership_Invalid(t *testing.T) {
+	team := &Team{Name: "optimize"}
+	tests := []struct {
+		teams []*Team
+		rules []*OwnershipRule
+	}{
+		{[]*Team{{}}, nil},
+		{[]*Team{team, {Name: "optimize"}}, nil},
+		{[]*Team{team}, []*OwnershipRule{{Job: "optimize-*"}}},
+		{[]*Team{team}, []*OwnershipRule{{Team: "optimize"}}},
+		{[]*Team{team}, []*OwnershipRule{{Team: "optimize", Job: "optimize-*", Regex: "^optimize"}}},
+		{[]*Team{team}, []*OwnershipRule{{Team: "optimize", Job: "[optimize"}}},
+		{[]*Team{team}, []*OwnershipRule{{Team: "optimize", Regex: "(optimize"}}},
+		{[]*Team{team}, []*OwnershipRule{{Team: "platform", Job: "*"}}},
+	}
+	for i, test := range tests {
+		if _, err := NewOwnership(test.teams, test.rules); err == nil {
+			t.Errorf("Ownership #%d should be invalid", i)
+		}
+	}
+}
+
+func TestDashboard_FetchFiltered(t *testing.T) {
+	provider := &TestProvider{name: "Release", jobs: []Job{
+		{ID: "camunda-optimize-docs", Color: "red", Broken: true},
+		{ID: "camunda-bpm-platform", Color: "red", Broken: true},
+		{ID: "zeebe", Color: "red", Broken: true},
+	}}
+	d := New(provider)
+	ownership, _ := NewOwnership([]*Team{{Name: "optimize"}, {Name: "platform"}}, []*OwnershipRule{
+		{Team: "optimize", Job: "camunda-optimize-*"},
+		{Team: "platform", Job: "camunda-*"},
+	})
+	d.UseOwnership(ownership)
+
+	jobs := d.Fetch()[0].Jobs
+	if len(jobs) != 3 || jobs[0].Owner.Name != "optimize" || jobs[1].Owner.Name != "platform" || jobs[2].Owner != nil {
+		t.Fatalf("Jobs should be attached to their owners, got %+v", jobs)
+	}
+
+	jobs = d.FetchFiltered(Filter{Team: "optimize"})[0].Jobs
+	if len(jobs) != 1 || jobs[0].ID != "camunda-optimize-docs" {
+		t.Fatalf("Jobs should be filtered by team, got %+v", jobs)
+	}
+	if aggregations := d.FetchFiltered(Filter{Team: "zeebe"}); len(aggregations) != 1 || len(aggregations[0].Jobs) != 0 {
+		t.Fatalf("Instances should be kept without the jobs of other teams, got %+v", aggregations)
+	}
+}
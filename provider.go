@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider is an instance of a CI system whose jobs are shown on the dashboard, e.g. a Jenkins server or a Travis
+// organization. New CI systems are added by implementing a Provider and registering a ProviderFactory for its type.
+type Provider interface {
+	// Info describes the instance by its type, name and URL. The name has to be unique across all providers.
+	Info() Aggregation
+	// Validate checks the configuration of the instance and returns a descriptive error for a misconfiguration.
+	Validate() error
+	// Fetch retrieves the broken jobs of the instance along with its health. Claims and mutes are applied by the
+	// Dashboard, so the jobs must neither be claimed nor muted by the provider, apart from claims of the CI system itself.
+	Fetch() *InstanceAggregation
+}
+
+// InstanceAggregation holds all dashboard relevant informations for an instance of any CI system.
+type InstanceAggregation struct {
+	Aggregation
+	Jobs  []Job `json:"jobs"`
+	Muted []Job `json:"muted"`
+	// Details holds the information which is specific to the CI system, e.g. the build queue size of Jenkins.
+	Details interface{} `json:"details,omitempty"`
+}
+
+// copy returns a copy of the aggregation, whose jobs can be modified without affecting the original.
+func (a *InstanceAggregation) copy() *InstanceAggregation {
+	c := *a
+	c.Jobs = append(make([]Job, 0, len(a.Jobs)), a.Jobs...)
+	c.Muted = append(make([]Job, 0, len(a.Muted)), a.Muted...)
+	return &c
+}
+
+// Job is a job of any CI system.
+type Job struct {
+	// ID identifies the job inside its instance, it's used to claim and mute the job.
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Color string `json:"color"`
+	// Running is true, if the job is currently building. The color is the one of the last completed build then.
+	Running bool `json:"running"`
+	// Broken is false for successful jobs which are reported nevertheless, e.g. the blue jobs of the Jenkins Broken view,
+	// and for jobs whose state is unknown.
+	Broken bool `json:"broken"`
+	// Error describes why the state of the job couldn't be retrieved.
+	Error string `json:"error,omitempty"`
+	// BrokenSince is the time the dashboard first saw the job broken.
+	BrokenSince *time.Time `json:"brokenSince,omitempty"`
+	Claim       *Claim     `json:"claim,omitempty"`
+	Mute        *Mute      `json:"mute,omitempty"`
+	// Owner is the team owning the job according to the ownership rules.
+	Owner *Team `json:"owner,omitempty"`
+	// Details is the job as reported by the CI system, e.g. a TravisJob.
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Unknown returns true, if the state of the job couldn't be retrieved, e.g. for the placeholder of a repository whose
+// request failed. Unknown jobs are neither broken nor successful.
+func (j Job) Unknown() bool {
+	return j.Error != ""
+}
+
+// displayName joins the non-empty parts of the name of a job, e.g. its repository and branch.
+func displayName(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " » ")
+}
+
+// ProviderConfig is the configuration of a single instance, as found in the config file.
+type ProviderConfig map[string]interface{}
+
+// Decode stores the configuration in the value pointed to by v. Keys are matched case-insensitively against the
+// fields of v, like encoding/json does.
+func (c ProviderConfig) Decode(v interface{}) error {
+	content, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, v)
+}
+
+// ProviderFactory creates the Provider with the given name from its configuration.
+type ProviderFactory func(name string, config ProviderConfig) (Provider, error)
+
+var (
+	providerFactoriesMutex sync.RWMutex
+	providerFactories      = make(map[string]ProviderFactory)
+)
+
+// RegisterProvider makes the providers of the given type available to NewProvider.
+// It panics, if a factory is registered twice for the same type.
+func RegisterProvider(providerType string, factory ProviderFactory) {
+	providerFactoriesMutex.Lock()
+	defer providerFactoriesMutex.Unlock()
+
+	if _, ok := providerFactories[providerType]; ok {
+		panic(fmt.Sprintf("Provider type '%s' is registered twice", providerType))
+	}
+	providerFactories[providerType] = factory
+}
+
+// ProviderTypes returns the sorted types of all registered providers.
+func ProviderTypes() []string {
+	providerFactoriesMutex.RLock()
+	defer providerFactoriesMutex.RUnlock()
+
+	types := make([]string, 0, len(providerFactories))
+	for providerType := range providerFactories {
+		types = append(types, providerType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewProvider creates the Provider with the given name using the factory registered for its type.
+func NewProvider(providerType string, name string, config ProviderConfig) (Provider, error) {
+	providerFactoriesMutex.RLock()
+	factory, ok := providerFactories[providerType]
+	providerFactoriesMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("Instance '%s' has the unknown type '%s', known types are %v.", name, providerType, ProviderTypes())
+	}
+
+	provider, err := factory(name, config)
+	if err != nil {
+		return nil, fmt.Errorf("Instance '%s' of type '%s' is misconfigured: %s", name, providerType, err)
+	}
+	return provider, nil
+}
@@ -0,0 +1,134 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider("travis", "camunda", ProviderConfig{
+		"webUrl": "https://travis.example.com/",
+		"repos": []interface{}{
+			map[string]interface{}{"name": "zeebe", "branches": []interface{}{"master", "release/*"}},
+			map[string]interface{}{"name": "operate"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, ok := provider.(*TravisInstance)
+	if !ok {
+		t.Fatalf("Wrong provider returned, got %T", provider)
+	}
+	expected := []TravisRepository{
+		{Organization: "camunda", Name: "zeebe", Branch: "master"},
+		{Organization: "camunda", Name: "zeebe", Branch: "release/*"},
+		{Organization: "camunda", Name: "operate", Branch: "master"},
+	}
+	if !reflect.DeepEqual(instance.Repos, expected) {
+		t.Fatalf("Wrong repositories configured. Expected: %+v, got: %+v", expected, instance.Repos)
+	}
+	if info := provider.Info(); info.Type != "travis" || info.Url != "https://travis.example.com/camunda" {
+		t.Fatalf("Wrong info of provider, got %+v", info)
+	}
+}
+
+func TestNewProvider_KeysAreCaseInsensitive(t *testing.T) {
+	provider, err := NewProvider("jenkins", "Release", ProviderConfig{
+		"url":           "https://ci.example.com",
+		"brokenjobsurl": "https://ci.example.com/view/release",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if instance := provider.(*JenkinsInstance); instance.BrokenJobsUrl != "https://ci.example.com/view/release" {
+		t.Fatalf("Broken jobs URL should be configured, got %+v", instance)
+	}
+}
+
+func TestNewProvider_UnknownType(t *testing.T) {
+	_, err := NewProvider("bamboo", "Bamboo", ProviderConfig{})
+
+	if err == nil || !strings.Contains(err.Error(), "unknown type 'bamboo'") {
+		t.Fatalf("Unknown type should be rejected, got %v", err)
+	}
+}
+
+func TestNewProvider_Misconfigured(t *testing.T) {
+	_, err := NewProvider("travis", "camunda", ProviderConfig{"discover": map[string]interface{}{"include": "("}})
+
+	if err == nil || !strings.Contains(err.Error(), "Invalid include pattern") {
+		t.Fatalf("Invalid discovery should be rejected, got %v", err)
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	RegisterProvider("test", func(name string, config ProviderConfig) (Provider, error) {
+		return &TestProvider{name: name}, nil
+	})
+	defer delete(providerFactories, "test")
+
+	provider, err := NewProvider("test", "Test", ProviderConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Info().Name != "Test" {
+		t.Fatalf("Provider should be created by the registered factory, got %+v", provider.Info())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Registering a type twice should panic")
+		}
+	}()
+	RegisterProvider("test", nil)
+}
+
+func TestProviderTypes(t *testing.T) {
+	expected := []string{"exec", "github", "gitlab", "jenkins", "json", "travis"}
+
+	if types := ProviderTypes(); !reflect.DeepEqual(types, expected) {
+		t.Fatalf("Wrong provider types. Expected: %v, got: %v", expected, types)
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	if name := displayName("zeebe", "", "main"); name != "zeebe » main" {
+		t.Fatalf("Empty parts should be skipped, got '%s'", name)
+	}
+}
+
+/**
+ * Test implementation of Provider
+ */
+
+type TestProvider struct {
+	name   string
+	jobs   []Job
+	panics bool
+	// health is the state of the instance, HealthOk if empty.
+	health HealthState
+}
+
+func (p *TestProvider) Info() Aggregation {
+	return Aggregation{Type: "test", Name: p.name, Url: "https://ci.example.com"}
+}
+
+func (p *TestProvider) Validate() error {
+	return nil
+}
+
+func (p *TestProvider) Fetch() *InstanceAggregation {
+	if p.panics {
+		panic("unable to fetch jobs")
+	}
+	aggregation := &InstanceAggregation{Aggregation: p.Info(), Jobs: append([]Job{}, p.jobs...)}
+	aggregation.Health = newHealth(nil, nil, 0)
+	if p.health != "" {
+		aggregation.Health.State = p.health
+	}
+	return aggregation
+}
@@ -0,0 +1,75 @@
+package dashboard
+
+import (
+	"sort"
+)
+
+// Summary holds the totals of all instances along with the totals of every instance.
+type Summary struct {
+	Broken int `json:"broken"`
+	Muted  int `json:"muted"`
+	// Unknown is the number of jobs whose state couldn't be fetched, which count neither as broken nor as successful.
+	Unknown int `json:"unknown"`
+	// Queued and BusyExecutors are the build queue size and busy executors of all Jenkins instances.
+	Queued        int `json:"queued"`
+	BusyExecutors int `json:"busyExecutors"`
+	Instances     int `json:"instances"`
+	// InstancesDown is the number of instances which couldn't be reached at all.
+	InstancesDown int `json:"instancesDown"`
+	// Providers are the totals of every instance, the one with the most broken jobs first.
+	Providers []*ProviderSummary `json:"providers"`
+	// Aggregations are the instances the totals are computed from, if they are served along with the summary.
+	Aggregations []*InstanceAggregation `json:"aggregations,omitempty"`
+}
+
+// ProviderSummary holds the totals of a single instance.
+type ProviderSummary struct {
+	Aggregation
+	Broken        int `json:"broken"`
+	Muted         int `json:"muted"`
+	Unknown       int `json:"unknown"`
+	Queued        int `json:"queued"`
+	BusyExecutors int `json:"busyExecutors"`
+}
+
+// Summary retrieves the broken jobs selected by the filter from all configured Provider's and sums them up.
+func (d *Dashboard) Summary(filter Filter) *Summary {
+	return Summarize(d.FetchFiltered(filter))
+}
+
+// Summarize sums up the given aggregations. Successful jobs which are reported nevertheless don't count as broken,
+// jobs whose state couldn't be fetched are counted as unknown.
+func Summarize(aggregations []*InstanceAggregation) *Summary {
+	summary := &Summary{Instances: len(aggregations), Providers: make([]*ProviderSummary, 0, len(aggregations))}
+
+	for _, aggregation := range aggregations {
+		provider := &ProviderSummary{Aggregation: aggregation.Aggregation, Muted: len(aggregation.Muted)}
+		for _, job := range aggregation.Jobs {
+			switch {
+			case job.Unknown():
+				provider.Unknown++
+			case job.Broken:
+				provider.Broken++
+			}
+		}
+		if details, ok := aggregation.Details.(JenkinsDetails); ok {
+			provider.Queued = details.BuildQueueSize
+			provider.BusyExecutors = details.BusyExecutors
+		}
+
+		summary.Broken += provider.Broken
+		summary.Muted += provider.Muted
+		summary.Unknown += provider.Unknown
+		summary.Queued += provider.Queued
+		summary.BusyExecutors += provider.BusyExecutors
+		if !aggregation.Health.IsAvailable() {
+			summary.InstancesDown++
+		}
+		summary.Providers = append(summary.Providers, provider)
+	}
+
+	sort.SliceStable(summary.Providers, func(i, j int) bool {
+		return summary.Providers[i].Broken > summary.Providers[j].Broken
+	})
+	return summary
+}
@@ -0,0 +1,54 @@
+package dashboard
+
+import (
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	aggregations := []*InstanceAggregation{
+		{
+			Aggregation: Aggregation{Name: "Release", Type: "jenkins", Health: Health{State: HealthOk}},
+			Jobs:        []Job{{ID: "docs", Broken: true}, {ID: "website", Color: "blue"}},
+			Muted:       []Job{{ID: "flaky", Broken: true}},
+			Details:     JenkinsDetails{BusyExecutors: 3, BuildQueueSize: 7},
+		},
+		{
+			Aggregation: Aggregation{Name: "camunda", Type: "travis", Health: Health{State: HealthDegraded}},
+			Jobs: []Job{
+				{ID: "zeebe@master", Broken: true}, {ID: "camunda-bpm@master", Broken: true},
+				{ID: "operate@master", Color: "grey", Broken: true, Error: "access denied"},
+			},
+		},
+		{
+			Aggregation: Aggregation{Name: "CI", Type: "jenkins", Health: Health{State: HealthDown}},
+			Details:     JenkinsDetails{BusyExecutors: 1},
+		},
+	}
+
+	summary := Summarize(aggregations)
+	if summary.Broken != 3 || summary.Muted != 1 || summary.Unknown != 1 || summary.Queued != 7 || summary.BusyExecutors != 4 ||
+		summary.Instances != 3 || summary.InstancesDown != 1 {
+		t.Fatalf("Wrong totals, got %+v", summary)
+	}
+
+	if len(summary.Providers) != 3 || summary.Providers[0].Name != "camunda" || summary.Providers[1].Name != "Release" ||
+		summary.Providers[2].Name != "CI" {
+		t.Fatalf("Providers should be sorted by broken jobs, got %+v", summary.Providers)
+	}
+	if release := summary.Providers[1]; release.Broken != 1 || release.Muted != 1 || release.Queued != 7 || release.BusyExecutors != 3 {
+		t.Fatalf("Wrong totals of provider, got %+v", release)
+	}
+}
+
+func TestDashboard_Summary(t *testing.T) {
+	provider := &TestProvider{name: "Release", jobs: []Job{{ID: "docs", Color: "red", Broken: true}}}
+	d := New(provider)
+	d.setPolling(true)
+
+	if summary := d.Summary(Filter{}); summary.Broken != 1 || summary.Providers[0].Name != "Release" {
+		t.Fatalf("Wrong summary, got %+v", summary)
+	}
+	if summary := d.Summary(Filter{Team: "optimize"}); summary.Broken != 0 {
+		t.Fatalf("Summary should be filtered, got %+v", summary)
+	}
+}
@@ -0,0 +1,173 @@
+package dashboard
+
+import (
+	"sort"
+	"time"
+)
+
+// historyRetention is how long the events are kept for the digests.
+const historyRetention = 8 * 24 * time.Hour
+
+// trackedJob is a broken job along with the time it was first seen broken.
+type trackedJob struct {
+	Job
+	since time.Time
+}
+
+// UseSinks sends the transitions of jobs and instances to the given sinks.
+func (d *Dashboard) UseSinks(sinks ...*Sink) {
+	d.sinks = append(d.sinks, sinks...)
+}
+
+// transitions returns the events between the previous and the current aggregation of an instance and remembers the
+// broken jobs of the current one along with the time they broke. The events are also recorded in the history of the
+// dashboard. The first aggregation of an instance doesn't cause any events, as well as jobs
+// whose state is unknown, e.g. because their repository couldn't be fetched. It must be called with the state locked.
+func (d *Dashboard) transitions(previous *InstanceAggregation, current *InstanceAggregation, now time.Time) []Event {
+	newEvent := func(eventType string) Event {
+		return Event{Type: eventType, Instance: current.Name, InstanceType: current.Type, InstanceUrl: current.Url, Time: now}
+	}
+
+	var events []Event
+	if previous != nil && previous.Health.IsAvailable() != current.Health.IsAvailable() {
+		event := newEvent(EventUp)
+		if !current.Health.IsAvailable() {
+			event.Type = EventDown
+		}
+		health := current.Health
+		event.Health = &health
+		events = append(events, event)
+	}
+	if !current.Health.IsAvailable() {
+		d.record(events, now)
+		return events
+	}
+
+	if d.brokenJobs == nil {
+		d.brokenJobs = make(map[string]map[string]trackedJob)
+	}
+	broken, known := d.brokenJobs[current.Name]
+
+	next := make(map[string]trackedJob)
+	succeeded := make(map[string]Job)
+	for _, job := range current.Jobs {
+		switch {
+		case job.Unknown():
+			// the state of the job is unknown, so it keeps its previous one
+			if last, ok := broken[job.ID]; ok {
+				next[job.ID] = last
+			}
+		case !job.Broken:
+			succeeded[job.ID] = job
+		default:
+			since := now
+			if last, ok := broken[job.ID]; ok {
+				since = last.since
+			}
+			next[job.ID] = trackedJob{Job: job, since: since}
+			if _, ok := broken[job.ID]; known && !ok {
+				brokenJob := job
+				event := newEvent(EventBroken)
+				event.Job = &brokenJob
+				events = append(events, event)
+			}
+		}
+	}
+
+	ids := make([]string, 0, len(broken))
+	for id := range broken {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, ok := next[id]; ok {
+			continue
+		}
+
+		// a missing job is only fixed, if all jobs of the instance could be fetched
+		job, ok := succeeded[id]
+		if !ok && !current.Health.IsOk() {
+			next[id] = broken[id]
+			continue
+		}
+		if !ok {
+			job = broken[id].Job
+			job.Broken = false
+		}
+		event := newEvent(EventFixed)
+		event.Job = &job
+		events = append(events, event)
+	}
+
+	d.brokenJobs[current.Name] = next
+	d.record(events, now)
+	return events
+}
+
+// record appends the events to the history and drops the ones older than the retention. It must be called with the
+// state locked.
+func (d *Dashboard) record(events []Event, now time.Time) {
+	d.history = append(d.history, events...)
+
+	expired := 0
+	for expired < len(d.history) && now.Sub(d.history[expired].Time) > historyRetention {
+		expired++
+	}
+	d.history = d.history[expired:]
+}
+
+// brokenSince returns the times the broken jobs of the instance were first seen broken, by their ID.
+func (d *Dashboard) brokenSince(instance string) map[string]time.Time {
+	d.stateMutex.RLock()
+	defer d.stateMutex.RUnlock()
+
+	since := make(map[string]time.Time, len(d.brokenJobs[instance]))
+	for id, job := range d.brokenJobs[instance] {
+		since[id] = job.since
+	}
+	return since
+}
+
+// applyBrokenSince sets the time the broken jobs of the aggregation were first seen broken.
+func (d *Dashboard) applyBrokenSince(aggregation *InstanceAggregation) {
+	since := d.brokenSince(aggregation.Name)
+	for i := range aggregation.Jobs {
+		job := &aggregation.Jobs[i]
+		if t, ok := since[job.ID]; ok && job.Broken {
+			brokenSince := t
+			job.BrokenSince = &brokenSince
+		}
+	}
+}
+
+// eventsSince returns the recorded events after the given time.
+func (d *Dashboard) eventsSince(since time.Time) []Event {
+	d.stateMutex.RLock()
+	defer d.stateMutex.RUnlock()
+
+	var events []Event
+	for _, event := range d.history {
+		if event.Time.After(since) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// notify passes the events to the sinks whose filters they match, along with the team owning the job. Events of
+// muted jobs aren't sent.
+func (d *Dashboard) notify(events []Event) {
+	for _, event := range events {
+		if event.Job != nil && d.mutes != nil && d.mutes.Find(event.Instance, event.Job.ID) != nil {
+			continue
+		}
+		if event.Job != nil && d.ownership != nil {
+			event.Job.Owner = d.ownership.OwnerOf(event.Instance, event.Job.ID)
+		}
+		for _, sink := range d.sinks {
+			if sink.Matches(event) {
+				sink.enqueue(event)
+			}
+		}
+	}
+}
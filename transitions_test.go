@@ -0,0 +1,147 @@
+package dashboard
+
+import (
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestDashboard_Transitions(t *testing.T) {
+	d := New()
+	now := time.Now()
+	ok := Health{State: HealthOk}
+
+	first := &InstanceAggregation{Aggregation: Aggregation{Name: "Release", Type: "jenkins", Health: ok}, Jobs: []Job{
+		{ID: "docs", Color: "red", Broken: true},
+		{ID: "website", Color: "red", Broken: true},
+	}}
+	if events := d.transitions(nil, first, now); len(events) != 0 {
+		t.Fatalf("First aggregation shouldn't cause any events, got %+v", events)
+	}
+
+	second := &InstanceAggregation{Aggregation: Aggregation{Name: "Release", Type: "jenkins", Health: ok}, Jobs: []Job{
+		{ID: "deploy", Color: "red", Broken: true},
+		{ID: "docs", Color: "blue"},
+	}}
+	events := d.transitions(first, second, now)
+	assertEvents(t, events, "broken deploy", "fixed docs", "fixed website")
+	if events[2].Job.Color != "red" || events[2].InstanceType != "jenkins" || !events[2].Time.Equal(now) {
+		t.Fatalf("Fixed job which isn't reported anymore should be described by its last state, got %+v", events[2])
+	}
+}
+
+func TestDashboard_Transitions_Health(t *testing.T) {
+	d := New()
+	now := time.Now()
+
+	up := &InstanceAggregation{Aggregation: Aggregation{Name: "camunda", Health: Health{State: HealthOk}}, Jobs: []Job{
+		{ID: "zeebe@master", Color: "red", Broken: true},
+	}}
+	d.transitions(nil, up, now)
+
+	down := &InstanceAggregation{Aggregation: Aggregation{Name: "camunda", Health: Health{State: HealthDown, Error: "timeout"}}}
+	events := d.transitions(up, down, now)
+	assertEvents(t, events, "down")
+	if events[0].Health == nil || events[0].Health.Error != "timeout" {
+		t.Fatalf("Down event should carry the health, got %+v", events[0])
+	}
+
+	// jobs whose state is unknown keep their state, missing jobs are only fixed when the instance is healthy
+	degraded := &InstanceAggregation{Aggregation: Aggregation{Name: "camunda", Health: Health{State: HealthDegraded}}, Jobs: []Job{
+		{ID: "zeebe@master", Color: "grey", Broken: true, Error: "connection refused"},
+	}}
+	assertEvents(t, d.transitions(down, degraded, now), "up")
+
+	partial := &InstanceAggregation{Aggregation: Aggregation{Name: "camunda", Health: Health{State: HealthDegraded}}}
+	assertEvents(t, d.transitions(degraded, partial, now))
+
+	healthy := &InstanceAggregation{Aggregation: Aggregation{Name: "camunda", Health: Health{State: HealthOk}}}
+	assertEvents(t, d.transitions(partial, healthy, now), "fixed zeebe@master")
+}
+
+func TestDashboard_Transitions_BrokenSince(t *testing.T) {
+	provider := &TestProvider{name: "Release", jobs: []Job{{ID: "docs", Color: "red", Broken: true}}}
+	d := New(provider)
+
+	first := d.Fetch()[0].Jobs[0]
+	if first.BrokenSince == nil {
+		t.Fatalf("Broken job should be broken since its first fetch, got %+v", first)
+	}
+
+	provider.jobs = append(provider.jobs, Job{ID: "website", Color: "red", Broken: true}, Job{ID: "deploy", Color: "blue"})
+	jobs := d.Fetch()[0].Jobs
+	if !jobs[0].BrokenSince.Equal(*first.BrokenSince) || jobs[1].BrokenSince == nil || jobs[2].BrokenSince != nil {
+		t.Fatalf("Broken jobs should keep the time they broke, got %+v", jobs)
+	}
+	if events := d.eventsSince(time.Time{}); len(events) != 1 || events[0].Type != EventBroken {
+		t.Fatalf("Events should be recorded, got %+v", events)
+	}
+}
+
+func TestDashboard_Record(t *testing.T) {
+	d := New()
+	now := time.Now()
+
+	d.record([]Event{{Type: EventBroken, Time: now.Add(-9 * 24 * time.Hour)}, {Type: EventFixed, Time: now.Add(-time.Hour)}}, now)
+	if len(d.history) != 1 || d.history[0].Type != EventFixed {
+		t.Fatalf("Expired events should be dropped, got %+v", d.history)
+	}
+}
+
+func TestDashboard_Fetch_Notifies(t *testing.T) {
+	provider := &TestProvider{name: "Release"}
+	notifier := &TestNotifier{events: make(chan Event, 10)}
+	muted := &TestNotifier{events: make(chan Event, 10)}
+
+	d := New(provider)
+	mutes, _ := NewMuteStore("", []*Mute{{Instance: "Release", Job: "flaky-*"}})
+	d.UseMutes(mutes)
+	d.UseSinks(&Sink{Name: "Alerts", Notifier: notifier, Template: mustMessageTemplate(t)},
+		&Sink{Name: "Docs", Notifier: muted, Template: mustMessageTemplate(t), Jobs: []string{"docs"}})
+	d.Fetch()
+
+	provider.jobs = []Job{{ID: "deploy", Name: "deploy", Color: "red", Broken: true}, {ID: "flaky-it", Color: "red", Broken: true}}
+	d.Fetch()
+
+	select {
+	case event := <-notifier.events:
+		if event.Type != EventBroken || event.Job.ID != "deploy" {
+			t.Fatalf("Broken job should be notified, got %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Broken job wasn't notified")
+	}
+	select {
+	case event := <-notifier.events:
+		t.Fatalf("Muted job shouldn't be notified, got %+v", event)
+	case event := <-muted.events:
+		t.Fatalf("Filtered job shouldn't be notified, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func assertEvents(t *testing.T, events []Event, expected ...string) {
+	actual := make([]string, len(events))
+	for i, event := range events {
+		actual[i] = event.Type
+		if event.Job != nil {
+			actual[i] += " " + event.Job.ID
+		}
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Wrong events. Expected: %v, got: %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Fatalf("Wrong events. Expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func mustMessageTemplate(t *testing.T) *template.Template {
+	tmpl, err := newMessageTemplate("test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
@@ -2,91 +2,565 @@ package dashboard
 
 import (
 	"context"
+	"fmt"
 	"github.com/shuheiktgw/go-travis"
+	"path"
+	"strings"
+	"time"
 )
 
 const (
-	travisUrl    = "https://travis-ci.org/"
-	TravisApiUrl = travis.ApiOrgUrl
+	// TravisApiUrl is the default API of travis-ci.com, travis-ci.org has been shut down.
+	TravisApiUrl = travis.ApiComUrl
+	// TravisWebUrl is the default web interface of travis-ci.com.
+	TravisWebUrl = "https://travis-ci.com/"
+
+	travisBranchesPageSize = 100
+	// travisDefaultBranch is the branch watched unless other branches are configured.
+	travisDefaultBranch = "master"
+)
+
+// The states of a Travis build. Builds are created, received and queued before they are started
+// and end up as either passed, failed, errored or canceled.
+const (
+	TravisStateCreated  = "created"
+	TravisStateReceived = "received"
+	TravisStateQueued   = "queued"
+	TravisStateStarted  = "started"
+	TravisStatePassed   = "passed"
+	TravisStateFailed   = "failed"
+	TravisStateErrored  = "errored"
+	TravisStateCanceled = "canceled"
 )
 
 type TravisInstance struct {
-	Name   string
+	Name string
+	// WebUrl is the web interface of the Travis installation, defaults to TravisWebUrl.
+	WebUrl string
 	Repos  []TravisRepository
-	Client Travis
+	// Discovery adds the active repositories of the organization to the configured ones, if set.
+	Discovery *TravisDiscovery
+	// WebhookToken authenticates the webhooks sent by Travis, webhooks are rejected without it.
+	WebhookToken string
+	Client       Travis
+}
+
+func init() {
+	RegisterProvider("travis", newTravisProvider)
+}
+
+// newTravisProvider creates a TravisInstance for the organization with the given name from the config keys 'apiUrl',
+// 'webUrl', 'accessToken', 'webhookToken', 'discover' and 'repos'. Repositories are watched on their 'branch' and
+// 'branches', which default to 'master'.
+func newTravisProvider(name string, config ProviderConfig) (Provider, error) {
+	var cfg struct {
+		ApiUrl       string
+		WebUrl       string
+		AccessToken  string
+		WebhookToken string
+		Discover     *struct {
+			Include         string
+			Exclude         string
+			Branches        []string
+			RefreshInterval string
+		}
+		Repos []struct {
+			Name     string
+			Branch   string
+			Branches []string
+		}
+	}
+	if err := config.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	apiUrl := cfg.ApiUrl
+	if apiUrl == "" {
+		apiUrl = TravisApiUrl
+	}
+	webUrl := cfg.WebUrl
+	if webUrl == "" {
+		webUrl = TravisWebUrlOf(apiUrl)
+	}
+	instance := &TravisInstance{
+		Name:         name,
+		WebUrl:       webUrl,
+		WebhookToken: cfg.WebhookToken,
+		Client:       NewTravisClient(apiUrl, webUrl, cfg.AccessToken),
+	}
+
+	if d := cfg.Discover; d != nil {
+		var refreshInterval time.Duration
+		if d.RefreshInterval != "" {
+			var err error
+			if refreshInterval, err = time.ParseDuration(d.RefreshInterval); err != nil {
+				return nil, fmt.Errorf("Invalid discovery refresh interval: %s", err)
+			}
+		}
+		discovery, err := NewTravisDiscovery(d.Include, d.Exclude, d.Branches, refreshInterval)
+		if err != nil {
+			return nil, err
+		}
+		instance.Discovery = discovery
+	}
+
+	for _, r := range cfg.Repos {
+		if r.Name == "" {
+			continue
+		}
+
+		branches := r.Branches
+		if r.Branch != "" {
+			branches = append([]string{r.Branch}, branches...)
+		}
+		if len(branches) == 0 {
+			branches = []string{travisDefaultBranch}
+		}
+
+		for _, branch := range branches {
+			instance.Repos = append(instance.Repos, TravisRepository{Organization: name, Name: r.Name, Branch: branch})
+		}
+	}
+
+	return instance, nil
+}
+
+func (t *TravisInstance) Info() Aggregation {
+	return Aggregation{Type: "travis", Name: t.Name, Url: t.Url()}
+}
+
+// Fetch retrieves the broken builds of the watched branches.
+func (t *TravisInstance) Fetch() *InstanceAggregation {
+	travisAggregation := getBrokenBuildsForTravisInstance(t)
+	aggregation := &InstanceAggregation{
+		Aggregation: travisAggregation.Aggregation,
+		Jobs:        make([]Job, 0, len(travisAggregation.Jobs)),
+	}
+
+	for _, job := range travisAggregation.Jobs {
+		aggregation.Jobs = append(aggregation.Jobs, travisJobOf(job))
+	}
+	return aggregation
+}
+
+// travisJobOf converts the branch of a repository into a Job.
+func travisJobOf(job TravisJob) Job {
+	return Job{
+		ID:      job.ID(),
+		Name:    displayName(job.Name, job.Branch),
+		URL:     job.URL,
+		Color:   job.Color,
+		Running: job.Running,
+		Broken:  job.Error == "" && !job.IsSuccessful(),
+		Error:   job.Error,
+		Details: job,
+	}
+}
+
+// travisAggregationOf converts the aggregation of an instance back into the TravisAggregation served by the Travis endpoint.
+func travisAggregationOf(aggregation *InstanceAggregation) *TravisAggregation {
+	return &TravisAggregation{
+		Aggregation: aggregation.Aggregation,
+		Jobs:        travisJobsOf(aggregation.Jobs),
+		Muted:       travisJobsOf(aggregation.Muted),
+	}
+}
+
+func travisJobsOf(jobs []Job) []TravisJob {
+	travisJobs := make([]TravisJob, 0, len(jobs))
+	for _, job := range jobs {
+		if travisJob, ok := job.Details.(TravisJob); ok {
+			travisJob.Claim = job.Claim
+			travisJob.Mute = job.Mute
+			travisJob.Owner = job.Owner
+			travisJobs = append(travisJobs, travisJob)
+		}
+	}
+	return travisJobs
 }
 
 func (t *TravisInstance) Url() string {
-	return travisUrl + t.Name
+	return travisWebUrl(t.WebUrl) + t.Name
+}
+
+// Validate checks that the instance is named and has a client to access Travis.
+func (t *TravisInstance) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("Travis organization has no name.")
+	}
+	if t.Client == nil {
+		return fmt.Errorf("Travis organization '%s' has no client.", t.Name)
+	}
+	if t.WebUrl != "" {
+		if err := validateUrl(t.WebUrl); err != nil {
+			return fmt.Errorf("Travis organization '%s' has an invalid web url: %s", t.Name, err)
+		}
+	}
+	for _, r := range t.Repos {
+		if r.Name == "" || r.Branch == "" {
+			return fmt.Errorf("Travis organization '%s' has a repository without name or branch.", t.Name)
+		}
+		if _, err := path.Match(r.Branch, ""); err != nil {
+			return fmt.Errorf("Travis repository '%s' has an invalid branch pattern '%s': %s", r.Slug(), r.Branch, err)
+		}
+	}
+	if t.Discovery != nil {
+		for _, branch := range t.Discovery.Branches {
+			if _, err := path.Match(branch, ""); err != nil {
+				return fmt.Errorf("Travis organization '%s' has an invalid discovery branch pattern '%s': %s", t.Name, branch, err)
+			}
+		}
+	}
+	return nil
 }
 
 // Holds all dashboard relevant informations for a Travis instance
 type TravisAggregation struct {
 	Aggregation
-	Jobs []TravisJob `json:"jobs"`
+	Jobs  []TravisJob `json:"jobs"`
+	Muted []TravisJob `json:"muted"`
 }
 
 type Travis interface {
 	Job(r TravisRepository) (TravisJob, error)
+	// Branches returns the names of all branches of the repository which still exist on GitHub.
+	Branches(r TravisRepository) ([]string, error)
+	// Repositories returns the names of all active repositories of the given owner.
+	Repositories(owner string) ([]string, error)
 }
 
-type TravisBuildStatus bool
-
+// TravisRepository is a branch of a repository to watch. The branch may be a glob pattern like 'release/*',
+// which is resolved to the matching branches through the Travis API.
 type TravisRepository struct {
 	Organization string
 	Name         string
 	Branch       string
 }
 
+// Slug returns the 'organization/name' identifier of the repository.
+func (r TravisRepository) Slug() string {
+	return r.Organization + "/" + r.Name
+}
+
+// IsPattern returns true, if the branch is a glob pattern instead of the name of a single branch.
+func (r TravisRepository) IsPattern() bool {
+	return strings.ContainsAny(r.Branch, "*?[")
+}
+
+// Matches returns true, if the given branch name is matched by the branch of the repository.
+func (r TravisRepository) Matches(branch string) bool {
+	matches, _ := path.Match(r.Branch, branch)
+	return matches
+}
+
 type TravisClient struct {
 	client *travis.Client
+	webUrl string
 }
 
 type TravisJob struct {
-	Name  string `json:"name"`
+	Name   string `json:"name"`
+	Branch string `json:"branch"`
+	// URL points at the last build of the branch, or at the repository if the branch hasn't been built yet.
 	URL   string `json:"url"`
 	Color string `json:"color"`
+	// BuildID, Number, State and Duration (in seconds) describe the last build of the branch.
+	BuildID  uint          `json:"buildId,omitempty"`
+	Number   string        `json:"number,omitempty"`
+	State    string        `json:"state,omitempty"`
+	Duration uint          `json:"duration,omitempty"`
+	Commit   *TravisCommit `json:"commit,omitempty"`
+	// Running is true, if the last build hasn't finished yet. The color is the one of the last completed build then.
+	Running       bool               `json:"running"`
+	LastCompleted *TravisBuildResult `json:"lastCompleted,omitempty"`
+	// FailedJobs are the jobs of the build matrix of the last completed build which failed and are not allowed to fail.
+	FailedJobs []TravisMatrixJob `json:"failedJobs,omitempty"`
+	// Error describes why the state of the repository couldn't be retrieved.
+	Error string `json:"error,omitempty"`
+	Claim *Claim `json:"claim,omitempty"`
+	Mute  *Mute  `json:"mute,omitempty"`
+	Owner *Team  `json:"owner,omitempty"`
+}
+
+// TravisBuildResult is the outcome of a completed build.
+type TravisBuildResult struct {
+	BuildID uint   `json:"buildId"`
+	Number  string `json:"number"`
+	State   string `json:"state"`
+	URL     string `json:"url"`
+}
+
+// TravisCommit is the commit a build was triggered for. Author is the login of the user who created the build.
+type TravisCommit struct {
+	Sha     string `json:"sha"`
+	Message string `json:"message"`
+	Author  string `json:"author,omitempty"`
+}
+
+// TravisMatrixJob is a single job of the build matrix of a Travis build.
+type TravisMatrixJob struct {
+	ID     uint   `json:"id"`
+	Number string `json:"number"`
+	State  string `json:"state"`
+	URL    string `json:"url"`
 }
 
 func (j TravisJob) IsSuccessful() bool {
 	return j.Color == "green" || j.Color == ""
 }
 
-func (c *TravisClient) status(r TravisRepository) (TravisBuildStatus, error) {
+// ID returns the name which identifies the job inside its Travis instance, i.e. 'repository@branch'. Jobs of the
+// default branch are identified by the repository only, as they were before other branches could be watched, so
+// that existing claims, mutes and ownership rules keep matching them.
+func (j TravisJob) ID() string {
+	if j.Branch == "" || j.Branch == travisDefaultBranch {
+		return j.Name
+	}
+	return j.Name + "@" + j.Branch
+}
+
+func (c *TravisClient) branch(r TravisRepository) (*travis.Branch, error) {
 	branch, _, err := c.client.Branches.FindByRepoSlug(
 		context.Background(),
-		r.Organization+"/"+r.Name,
+		r.Slug(),
 		r.Branch,
-		&travis.BranchOption{},
+		&travis.BranchOption{Include: []string{"branch.recent_builds", "build.commit", "build.created_by"}},
 	)
 
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func (c *TravisClient) failedJobs(r TravisRepository, buildID uint) ([]TravisMatrixJob, error) {
+	jobs, _, err := c.client.Jobs.ListByBuild(context.Background(), buildID)
+	if err != nil {
+		return nil, err
 	}
 
-	return *branch.LastBuild.State == "passed", nil
+	failed := make([]TravisMatrixJob, 0)
+	for _, job := range jobs {
+		state := stringValue(job.State)
+		if (state != TravisStateFailed && state != TravisStateErrored) || boolValue(job.AllowFailure) {
+			continue
+		}
+		id := uintValue(job.Id)
+		failed = append(failed, TravisMatrixJob{
+			ID:     id,
+			Number: stringValue(job.Number),
+			State:  state,
+			URL:    fmt.Sprintf("%s%s/jobs/%d", c.webUrl, r.Slug(), id),
+		})
+	}
+	return failed, nil
 }
 
 func (c *TravisClient) Job(r TravisRepository) (TravisJob, error) {
-	status, err := c.status(r)
+	branch, err := c.branch(r)
 	job := TravisJob{
-		Name: r.Name,
-		URL:  travisUrl + r.Organization + "/" + r.Name,
+		Name:   r.Name,
+		Branch: r.Branch,
+		URL:    c.webUrl + r.Slug(),
 	}
 
-	color := "red"
 	if err != nil {
-		color = "grey"
-	} else if status {
-		color = "green"
+		job.Color = "grey"
+		job.Error = err.Error()
+		return job, err
+	}
+	build := branch.LastBuild
+	if build == nil {
+		// the branch hasn't been built yet
+		return job, nil
+	}
+
+	job.BuildID = uintValue(build.Id)
+	job.Number = stringValue(build.Number)
+	job.State = stringValue(build.State)
+	job.Duration = uintValue(build.Duration)
+	job.URL = c.buildUrl(r, job.BuildID)
+	if build.Commit != nil {
+		job.Commit = &TravisCommit{Sha: stringValue(build.Commit.Sha), Message: stringValue(build.Commit.Message)}
+		if build.CreatedBy != nil {
+			job.Commit.Author = stringValue(build.CreatedBy.Login)
+		}
+	}
+
+	// a running build doesn't have a result yet, so the job keeps the result of the last completed build
+	completed := build
+	if IsTravisBuildRunning(job.State) {
+		job.Running = true
+		completed = lastCompletedBuild(branch.RecentBuilds)
+		if completed == nil {
+			return job, nil
+		}
+		job.LastCompleted = &TravisBuildResult{
+			BuildID: uintValue(completed.Id),
+			Number:  stringValue(completed.Number),
+			State:   stringValue(completed.State),
+		}
+		job.LastCompleted.URL = c.buildUrl(r, job.LastCompleted.BuildID)
 	}
 
-	job.Color = color
+	job.Color = travisColor(stringValue(completed.State))
+	if job.Color != "red" {
+		return job, nil
+	}
+
+	job.FailedJobs, err = c.failedJobs(r, uintValue(completed.Id))
+	if err != nil {
+		job.Error = fmt.Sprintf("Unable to retrieve the failed jobs of build #%s: %s", stringValue(completed.Number), err)
+	}
 	return job, err
 }
 
-func NewTravisClient(baseUrl string, apiToken string) Travis {
+func (c *TravisClient) buildUrl(r TravisRepository, buildID uint) string {
+	return fmt.Sprintf("%s%s/builds/%d", c.webUrl, r.Slug(), buildID)
+}
+
+// IsTravisBuildRunning returns true, if a build in the given state hasn't finished yet.
+func IsTravisBuildRunning(state string) bool {
+	switch state {
+	case TravisStateCreated, TravisStateReceived, TravisStateQueued, TravisStateStarted:
+		return true
+	}
+	return false
+}
+
+// travisColor maps the state of a completed build onto the color of the job.
+func travisColor(state string) string {
+	switch state {
+	case TravisStatePassed:
+		return "green"
+	case TravisStateFailed, TravisStateErrored:
+		return "red"
+	case TravisStateCanceled:
+		return "aborted"
+	}
+	return "grey"
+}
+
+// lastCompletedBuild returns the most recent of the given builds which has finished, or nil if there is none.
+func lastCompletedBuild(builds []*travis.Build) *travis.Build {
+	var last *travis.Build
+	for _, build := range builds {
+		if IsTravisBuildRunning(stringValue(build.State)) || build.Id == nil {
+			continue
+		}
+		if last == nil || *build.Id > *last.Id {
+			last = build
+		}
+	}
+	return last
+}
+
+func (c *TravisClient) Branches(r TravisRepository) ([]string, error) {
+	var names []string
+	for offset := 0; ; offset += travisBranchesPageSize {
+		branches, _, err := c.client.Branches.ListByRepoSlug(
+			context.Background(),
+			r.Slug(),
+			&travis.BranchesOption{ExistsOnGithub: true, Limit: travisBranchesPageSize, Offset: offset},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branch := range branches {
+			if branch.Name != nil {
+				names = append(names, *branch.Name)
+			}
+		}
+		if len(branches) < travisBranchesPageSize {
+			return names, nil
+		}
+	}
+}
+
+// resolveTravisBranches replaces every repository with a branch pattern by one repository per matching branch.
+// Repositories whose branches can't be listed are kept as they are and returned with their error.
+func resolveTravisBranches(client Travis, repos []TravisRepository) ([]TravisRepository, map[TravisRepository]error) {
+	resolved := make([]TravisRepository, 0, len(repos))
+	errs := make(map[TravisRepository]error)
+	seen := make(map[TravisRepository]bool)
+
+	add := func(r TravisRepository) {
+		if !seen[r] {
+			seen[r] = true
+			resolved = append(resolved, r)
+		}
+	}
+
+	for _, r := range repos {
+		if !r.IsPattern() {
+			add(r)
+			continue
+		}
+
+		branches, err := client.Branches(r)
+		if err != nil {
+			errs[r] = err
+			add(r)
+			continue
+		}
+		for _, branch := range branches {
+			if r.Matches(branch) {
+				add(TravisRepository{Organization: r.Organization, Name: r.Name, Branch: branch})
+			}
+		}
+	}
+
+	return resolved, errs
+}
+
+// NewTravisClient returns a client for the Travis API at apiUrl, which links the jobs to the web interface at webUrl.
+// Empty urls default to travis-ci.com.
+func NewTravisClient(apiUrl string, webUrl string, apiToken string) Travis {
+	if apiUrl == "" {
+		apiUrl = TravisApiUrl
+	}
 	return &TravisClient{
-		client: travis.NewClient(baseUrl, apiToken),
+		client: travis.NewClient(apiUrl, apiToken),
+		webUrl: travisWebUrl(webUrl),
 	}
 }
+
+// TravisWebUrlOf guesses the web interface of a Travis installation from its API url.
+// Travis Enterprise serves its API below '/api/' of the web interface.
+func TravisWebUrlOf(apiUrl string) string {
+	switch {
+	case apiUrl == "" || apiUrl == travis.ApiComUrl:
+		return TravisWebUrl
+	case apiUrl == travis.ApiOrgUrl:
+		return "https://travis-ci.org/"
+	}
+	return travisWebUrl(strings.TrimSuffix(strings.TrimSuffix(apiUrl, "/"), "/api"))
+}
+
+// travisWebUrl returns the given web url with a trailing slash, or the default web url if it is empty.
+func travisWebUrl(webUrl string) string {
+	if webUrl == "" {
+		return TravisWebUrl
+	}
+	return strings.TrimSuffix(webUrl, "/") + "/"
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func uintValue(u *uint) uint {
+	if u == nil {
+		return 0
+	}
+	return *u
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,143 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/shuheiktgw/go-travis"
+)
+
+const (
+	// DefaultTravisDiscoveryRefresh is the interval in which the repositories of an organization are discovered again.
+	DefaultTravisDiscoveryRefresh = time.Hour
+
+	travisRepositoriesPageSize = 100
+)
+
+// TravisDiscovery discovers the active repositories of a Travis organization.
+// Discovered repositories are watched on the configured branches, unless they are configured explicitly.
+type TravisDiscovery struct {
+	// Include and Exclude filter the discovered repositories by name, nil matches all respectively none.
+	Include *regexp.Regexp
+	Exclude *regexp.Regexp
+	// Branches are the branches or branch patterns to watch for every discovered repository.
+	Branches []string
+	// RefreshInterval is the time after which the repositories are discovered again.
+	RefreshInterval time.Duration
+
+	mutex       sync.Mutex
+	repos       []string
+	lastRefresh time.Time
+}
+
+// NewTravisDiscovery returns a TravisDiscovery with the given name filters. Empty filters are ignored,
+// without branches the 'master' branch is watched and without refresh interval DefaultTravisDiscoveryRefresh is used.
+func NewTravisDiscovery(include string, exclude string, branches []string, refreshInterval time.Duration) (*TravisDiscovery, error) {
+	discovery := &TravisDiscovery{Branches: branches, RefreshInterval: refreshInterval}
+
+	var err error
+	if include != "" {
+		if discovery.Include, err = regexp.Compile(include); err != nil {
+			return nil, fmt.Errorf("Invalid include pattern '%s': %s", include, err)
+		}
+	}
+	if exclude != "" {
+		if discovery.Exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, fmt.Errorf("Invalid exclude pattern '%s': %s", exclude, err)
+		}
+	}
+	if len(discovery.Branches) == 0 {
+		discovery.Branches = []string{travisDefaultBranch}
+	}
+	if discovery.RefreshInterval <= 0 {
+		discovery.RefreshInterval = DefaultTravisDiscoveryRefresh
+	}
+
+	return discovery, nil
+}
+
+// Matches returns true, if the repository with the given name passes the include and exclude filters.
+func (d *TravisDiscovery) Matches(name string) bool {
+	if d.Include != nil && !d.Include.MatchString(name) {
+		return false
+	}
+	return d.Exclude == nil || !d.Exclude.MatchString(name)
+}
+
+// discover returns the names of the matching repositories, which are only requested again after the refresh interval.
+// If the repositories can't be requested, the previously discovered ones are returned along with the error.
+func (d *TravisDiscovery) discover(client Travis, organization string, now time.Time) ([]string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if !d.lastRefresh.IsZero() && now.Sub(d.lastRefresh) < d.RefreshInterval {
+		return d.repos, nil
+	}
+
+	names, err := client.Repositories(organization)
+	if err != nil {
+		return d.repos, err
+	}
+
+	d.repos = make([]string, 0, len(names))
+	for _, name := range names {
+		if d.Matches(name) {
+			d.repos = append(d.repos, name)
+		}
+	}
+	d.lastRefresh = now
+	return d.repos, nil
+}
+
+// repositories returns the configured repositories of the instance merged with the discovered ones.
+// Branches of explicitly configured repositories take precedence over the branches of the discovery.
+func (t *TravisInstance) repositories(now time.Time) ([]TravisRepository, error) {
+	if t.Discovery == nil {
+		return t.Repos, nil
+	}
+
+	names, err := t.Discovery.discover(t.Client, t.Name, now)
+
+	configured := make(map[string]bool)
+	for _, r := range t.Repos {
+		configured[r.Name] = true
+	}
+
+	repos := append([]TravisRepository{}, t.Repos...)
+	for _, name := range names {
+		if configured[name] {
+			continue
+		}
+		for _, branch := range t.Discovery.Branches {
+			repos = append(repos, TravisRepository{Organization: t.Name, Name: name, Branch: branch})
+		}
+	}
+
+	return repos, err
+}
+
+func (c *TravisClient) Repositories(owner string) ([]string, error) {
+	var names []string
+	for offset := 0; ; offset += travisRepositoriesPageSize {
+		repos, _, err := c.client.Repositories.ListByOwner(
+			context.Background(),
+			owner,
+			&travis.RepositoriesOption{Limit: travisRepositoriesPageSize, Offset: offset},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, repo := range repos {
+			if repo.Name != nil && boolValue(repo.Active) {
+				names = append(names, *repo.Name)
+			}
+		}
+		if len(repos) < travisRepositoriesPageSize {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package dashboard
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTravisClient_Repositories(t *testing.T) {
+	server := mockSuccesfulResponseWithBodyFromFile("testdata/travis/repositories.json", t)
+	defer server.Close()
+
+	tc := NewTravisClient(server.URL+"/", "", "")
+	repos, err := tc.Repositories("camunda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"camunda-external-task-client-js", "camunda-bpm-assert-scenario"}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Fatalf("Only active repositories should be returned. Expected: %v, got: %v", expected, repos)
+	}
+}
+
+func TestNewTravisDiscovery(t *testing.T) {
+	discovery, err := NewTravisDiscovery("^camunda-", "-js$", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !discovery.Matches("camunda-bpm-assert") || discovery.Matches("camunda-external-task-client-js") ||
+		discovery.Matches("zeebe") {
+		t.Fatal("Repositories should be filtered by the include and exclude patterns")
+	}
+	if !reflect.DeepEqual(discovery.Branches, []string{"master"}) || discovery.RefreshInterval != DefaultTravisDiscoveryRefresh {
+		t.Fatalf("Discovery should use the defaults, got %+v", discovery)
+	}
+
+	if _, err := NewTravisDiscovery("(", "", nil, 0); err == nil {
+		t.Fatal("Invalid include pattern should be rejected")
+	}
+}
+
+func TestTravisDiscovery_RefreshesAfterInterval(t *testing.T) {
+	client := &TestTravisClient{repositories: []string{"repo1"}}
+	discovery, _ := NewTravisDiscovery("", "", nil, time.Hour)
+	now := time.Now()
+
+	discovery.discover(client, "org", now)
+	client.repositories = []string{"repo1", "repo2"}
+
+	repos, _ := discovery.discover(client, "org", now.Add(30*time.Minute))
+	if len(repos) != 1 || client.discoveries != 1 {
+		t.Fatalf("Repositories should be cached until the refresh interval passed, got %v", repos)
+	}
+
+	repos, _ = discovery.discover(client, "org", now.Add(61*time.Minute))
+	if len(repos) != 2 || client.discoveries != 2 {
+		t.Fatalf("Repositories should be discovered again after the refresh interval, got %v", repos)
+	}
+
+	client.error = errors.New("service unavailable")
+	repos, err := discovery.discover(client, "org", now.Add(3*time.Hour))
+	if err == nil || len(repos) != 2 {
+		t.Fatalf("Previously discovered repositories should be returned with the error, got %v, %v", repos, err)
+	}
+}
+
+func TestTravisInstance_RepositoriesMergesConfiguredRepositories(t *testing.T) {
+	client := &TestTravisClient{repositories: []string{"repo1", "repo2"}}
+	discovery, _ := NewTravisDiscovery("", "", []string{"master", "release/*"}, 0)
+	instance := &TravisInstance{
+		Name:      "org",
+		Client:    client,
+		Discovery: discovery,
+		Repos:     []TravisRepository{{Organization: "org", Name: "repo1", Branch: "develop"}},
+	}
+
+	repos, err := instance.repositories(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []TravisRepository{
+		{Organization: "org", Name: "repo1", Branch: "develop"},
+		{Organization: "org", Name: "repo2", Branch: "master"},
+		{Organization: "org", Name: "repo2", Branch: "release/*"},
+	}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Fatalf("Wrong repositories returned. Expected: %v, got: %v", expected, repos)
+	}
+}
@@ -2,21 +2,59 @@ package dashboard
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestTravisJob(t *testing.T) {
 	dataMocks := []string{"testdata/travis/branch_passed.json", "testdata/travis/branch_failed.json"}
 	expected := []TravisJob{
-		{Name: "repo", URL: "https://travis-ci.org/org/repo", Color: "green"},
-		{Name: "repo", URL: "https://travis-ci.org/org/repo", Color: "red"},
+		{
+			Name:     "repo",
+			Branch:   "master",
+			URL:      "https://travis-ci.com/org/repo/builds/579688290",
+			Color:    "green",
+			BuildID:  579688290,
+			Number:   "191",
+			State:    "passed",
+			Duration: 257,
+			Commit: &TravisCommit{
+				Sha:     "5ac5bcb1c2a0e2ad6a2de3ab4c57cc0ff0ed67d4",
+				Message: "Merge pull request #121 from camunda/fix-typo",
+				Author:  "jane",
+			},
+		},
+		{
+			Name:     "repo",
+			Branch:   "master",
+			URL:      "https://travis-ci.com/org/repo/builds/596934039",
+			Color:    "red",
+			BuildID:  596934039,
+			Number:   "222",
+			State:    "failed",
+			Duration: 676,
+			Commit: &TravisCommit{
+				Sha:     "e7d1b0efa0d7f2f2a0fc7c66b3b3a7d61c2e2f9b",
+				Message: "chore(deps): update camunda-bpm to 7.12.0-alpha4",
+				Author:  "camunda-jenkins",
+			},
+			FailedJobs: []TravisMatrixJob{
+				{ID: 596934041, Number: "222.2", State: "failed", URL: "https://travis-ci.com/org/repo/jobs/596934041"},
+			},
+		},
 	}
 
 	for i := range dataMocks {
-		server := mockSuccesfulResponseWithBodyFromFile(dataMocks[i], t)
+		server := mockTravisServer(map[string]string{
+			"/branch/master":        dataMocks[i],
+			"/build/596934039/jobs": "testdata/travis/build_jobs.json",
+		}, t)
 
-		fmt.Println(server.URL)
-		tc := NewTravisClient(server.URL+"/", "")
+		tc := NewTravisClient(server.URL+"/", "", "")
 		repo := TravisRepository{Organization: "org", Name: "repo", Branch: "master"}
 		res, err := tc.Job(repo)
 		server.Close()
@@ -25,24 +63,198 @@ func TestTravisJob(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if res != expected[i] {
-			t.Fatalf("Wrong TravisJob returned. Expected: %v, got: %v", expected[i], res)
+		if !reflect.DeepEqual(res, expected[i]) {
+			t.Fatalf("Wrong TravisJob returned. Expected: %+v, got: %+v", expected[i], res)
 		}
 	}
 }
 
+func TestTravisJob_Running(t *testing.T) {
+	server := mockTravisServer(map[string]string{
+		"/branch/master":        "testdata/travis/branch_running.json",
+		"/build/596934039/jobs": "testdata/travis/build_jobs.json",
+	}, t)
+	defer server.Close()
+
+	tc := NewTravisClient(server.URL+"/", "", "")
+	res, err := tc.Job(TravisRepository{Organization: "org", Name: "repo", Branch: "master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Running || res.State != "started" || res.Number != "223" {
+		t.Fatalf("Running build should be reported as running, got %+v", res)
+	}
+	expected := &TravisBuildResult{
+		BuildID: 596934039,
+		Number:  "222",
+		State:   "failed",
+		URL:     "https://travis-ci.com/org/repo/builds/596934039",
+	}
+	if !reflect.DeepEqual(res.LastCompleted, expected) {
+		t.Fatalf("Wrong last completed build. Expected: %+v, got: %+v", expected, res.LastCompleted)
+	}
+	if res.Color != "red" || len(res.FailedJobs) != 1 {
+		t.Fatalf("Job should keep the result of the last completed build, got %+v", res)
+	}
+}
+
+func TestTravisColor(t *testing.T) {
+	cases := map[string]string{
+		"passed":   "green",
+		"failed":   "red",
+		"errored":  "red",
+		"canceled": "aborted",
+		"unknown":  "grey",
+	}
+
+	for state, expected := range cases {
+		if color := travisColor(state); color != expected {
+			t.Errorf("Wrong color for state '%s'. Expected: %s, got: %s", state, expected, color)
+		}
+	}
+
+	for _, state := range []string{"created", "received", "queued", "started"} {
+		if !IsTravisBuildRunning(state) {
+			t.Errorf("Build in state '%s' should be running", state)
+		}
+	}
+	if IsTravisBuildRunning("passed") {
+		t.Error("Passed build should not be running")
+	}
+}
+
+func TestTravisJob_FailedJobsNotAvailable(t *testing.T) {
+	server := mockTravisServer(map[string]string{"/branch/master": "testdata/travis/branch_failed.json"}, t)
+	defer server.Close()
+
+	tc := NewTravisClient(server.URL+"/", "", "")
+	res, err := tc.Job(TravisRepository{Organization: "org", Name: "repo", Branch: "master"})
+
+	if err == nil {
+		t.Fatal("Expecting an error to be returned, got nil")
+	}
+	if res.Color != "red" || res.BuildID != 596934039 || !strings.Contains(res.Error, "build #222") {
+		t.Fatalf("Build should still be reported as failed with the error, got %+v", res)
+	}
+}
+
 func TestTravisJob_ConnectionFailed(t *testing.T) {
 
-	tc := NewTravisClient("http://wrongUrl/", "")
+	tc := NewTravisClient("http://wrongUrl/", "", "")
 	repo := TravisRepository{Organization: "org", Name: "repo", Branch: "master"}
-	exp := TravisJob{Name: "repo", URL: "https://travis-ci.org/org/repo", Color: "grey"}
+	exp := TravisJob{Name: "repo", Branch: "master", URL: "https://travis-ci.com/org/repo", Color: "grey"}
 	res, err := tc.Job(repo)
 
 	if err == nil {
 		t.Fatal("Expecting an error to be returned, got nil")
 	}
 
-	if res != exp {
+	if res.Error != err.Error() {
+		t.Fatalf("Error should be part of the TravisJob. Expected: %s, got: %s", err, res.Error)
+	}
+	res.Error = ""
+
+	if !reflect.DeepEqual(res, exp) {
 		t.Fatalf("Wrong TravisJob returned. Expected: %v, got: %v", exp, res)
 	}
 }
+
+func TestTravisJob_WebUrl(t *testing.T) {
+	server := mockSuccesfulResponseWithBodyFromFile("testdata/travis/branch_passed.json", t)
+	defer server.Close()
+
+	tc := NewTravisClient(server.URL+"/", "https://travis.example.com", "")
+	repo := TravisRepository{Organization: "org", Name: "repo", Branch: "master"}
+	res, err := tc.Job(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.URL != "https://travis.example.com/org/repo/builds/579688290" {
+		t.Fatalf("Job should link to the configured web url, got: %s", res.URL)
+	}
+}
+
+func TestTravisWebUrlOf(t *testing.T) {
+	cases := map[string]string{
+		"":                                   "https://travis-ci.com/",
+		"https://api.travis-ci.com/":         "https://travis-ci.com/",
+		"https://api.travis-ci.org/":         "https://travis-ci.org/",
+		"https://travis.example.com/api/":    "https://travis.example.com/",
+		"https://travis.example.com/api":     "https://travis.example.com/",
+		"https://travis-api.example.com/v3/": "https://travis-api.example.com/v3/",
+	}
+
+	for apiUrl, expected := range cases {
+		if webUrl := TravisWebUrlOf(apiUrl); webUrl != expected {
+			t.Errorf("Wrong web url for '%s'. Expected: %s, got: %s", apiUrl, expected, webUrl)
+		}
+	}
+}
+
+func TestTravisInstance_Url(t *testing.T) {
+	instance := &TravisInstance{Name: "camunda"}
+	if instance.Url() != "https://travis-ci.com/camunda" {
+		t.Fatalf("Instance without web url should point to travis-ci.com, got: %s", instance.Url())
+	}
+
+	instance.WebUrl = "https://travis.example.com"
+	if instance.Url() != "https://travis.example.com/camunda" {
+		t.Fatalf("Instance should point to its web url, got: %s", instance.Url())
+	}
+}
+
+func TestTravisClient_Branches(t *testing.T) {
+	server := mockSuccesfulResponseWithBodyFromFile("testdata/travis/branches.json", t)
+	defer server.Close()
+
+	tc := NewTravisClient(server.URL+"/", "", "")
+	branches, err := tc.Branches(TravisRepository{Organization: "org", Name: "repo", Branch: "release/*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"master", "release/7.11", "release/7.12"}
+	if !reflect.DeepEqual(branches, expected) {
+		t.Fatalf("Wrong branches returned. Expected: %v, got: %v", expected, branches)
+	}
+}
+
+func TestTravisRepository_Matches(t *testing.T) {
+	repo := TravisRepository{Organization: "org", Name: "repo", Branch: "release/*"}
+
+	if !repo.IsPattern() {
+		t.Fatal("Branch with wildcard should be a pattern")
+	}
+	if !repo.Matches("release/7.12") || repo.Matches("master") || repo.Matches("release/7.12/hotfix") {
+		t.Fatal("Pattern should only match the release branches")
+	}
+	if (TravisRepository{Branch: "master"}).IsPattern() {
+		t.Fatal("Plain branch name should not be a pattern")
+	}
+}
+
+// mockTravisServer serves the fixtures for the requests whose path ends with the given suffixes, and 404 otherwise.
+func mockTravisServer(fixtures map[string]string, t *testing.T) *httptest.Server {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		for suffix, fileName := range fixtures {
+			if !strings.HasSuffix(r.URL.Path, suffix) {
+				continue
+			}
+			content, err := ioutil.ReadFile(fileName)
+			if err != nil {
+				t.Fatalf("Unable to read file: %s. Error: %s", fileName, err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(content)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"@type": "error", "error_type": "not_found", "error_message": "resource not found"}`)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(f))
+}
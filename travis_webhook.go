@@ -0,0 +1,109 @@
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// travisNotification is the payload of a Travis webhook, which is sent form encoded in the field 'payload'.
+type travisNotification struct {
+	ID         uint   `json:"id"`
+	Number     string `json:"number"`
+	State      string `json:"state"`
+	Duration   uint   `json:"duration"`
+	Type       string `json:"type"`
+	Branch     string `json:"branch"`
+	BuildURL   string `json:"build_url"`
+	Commit     string `json:"commit"`
+	Message    string `json:"message"`
+	AuthorName string `json:"author_name"`
+	Repository struct {
+		Name      string `json:"name"`
+		OwnerName string `json:"owner_name"`
+	} `json:"repository"`
+	Matrix []struct {
+		ID           uint   `json:"id"`
+		Number       string `json:"number"`
+		State        string `json:"state"`
+		AllowFailure bool   `json:"allow_failure"`
+	} `json:"matrix"`
+}
+
+// ReceiveWebhook handles the webhooks of Travis, which are authenticated by the webhook token. Only finished builds of
+// watched branches are reported, builds of pull requests are ignored.
+func (t *TravisInstance) ReceiveWebhook(r *http.Request, body []byte) ([]Job, error) {
+	if err := verifyWebhookToken(r, t.WebhookToken); err != nil {
+		return nil, err
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil || form.Get("payload") == "" {
+		return nil, webhookErrorf(http.StatusBadRequest, "Travis webhook has no payload.")
+	}
+	notification := &travisNotification{}
+	if err := json.Unmarshal([]byte(form.Get("payload")), notification); err != nil {
+		return nil, webhookErrorf(http.StatusBadRequest, "Invalid Travis webhook payload: %s", err)
+	}
+
+	if notification.Type == "pull_request" || IsTravisBuildRunning(notification.State) ||
+		!strings.EqualFold(notification.Repository.OwnerName, t.Name) || !t.watches(notification.Repository.Name, notification.Branch) {
+		return nil, nil
+	}
+
+	repo := TravisRepository{Organization: t.Name, Name: notification.Repository.Name, Branch: notification.Branch}
+	job := TravisJob{
+		Name:     repo.Name,
+		Branch:   repo.Branch,
+		URL:      notification.BuildURL,
+		Color:    travisColor(notification.State),
+		BuildID:  notification.ID,
+		Number:   notification.Number,
+		State:    notification.State,
+		Duration: notification.Duration,
+		Commit:   &TravisCommit{Sha: notification.Commit, Message: notification.Message, Author: notification.AuthorName},
+	}
+	if job.Color == "red" {
+		job.FailedJobs = make([]TravisMatrixJob, 0)
+		for _, matrixJob := range notification.Matrix {
+			if (matrixJob.State != TravisStateFailed && matrixJob.State != TravisStateErrored) || matrixJob.AllowFailure {
+				continue
+			}
+			job.FailedJobs = append(job.FailedJobs, TravisMatrixJob{
+				ID:     matrixJob.ID,
+				Number: matrixJob.Number,
+				State:  matrixJob.State,
+				URL:    fmt.Sprintf("%s%s/jobs/%d", travisWebUrl(t.WebUrl), repo.Slug(), matrixJob.ID),
+			})
+		}
+	}
+	return []Job{travisJobOf(job)}, nil
+}
+
+// watches returns true, if the branch of the repository with the given name is watched, either as configured or
+// discovered repository.
+func (t *TravisInstance) watches(name string, branch string) bool {
+	configured := false
+	for _, r := range t.Repos {
+		if r.Name != name {
+			continue
+		}
+		configured = true
+		if r.Matches(branch) {
+			return true
+		}
+	}
+	// branches of configured repositories take precedence over the ones of the discovery
+	if configured || t.Discovery == nil || !t.Discovery.Matches(name) {
+		return false
+	}
+	for _, pattern := range t.Discovery.Branches {
+		if matches, _ := path.Match(pattern, branch); matches {
+			return true
+		}
+	}
+	return false
+}
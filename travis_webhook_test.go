@@ -0,0 +1,97 @@
+package dashboard
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTravisInstance_ReceiveWebhook(t *testing.T) {
+	instance := &TravisInstance{
+		Name:         "camunda",
+		Repos:        []TravisRepository{{Organization: "camunda", Name: "zeebe", Branch: "master"}},
+		WebhookToken: "secret",
+	}
+
+	jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), travisWebhookBody(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jobs) != 1 || jobs[0].ID != "zeebe" || jobs[0].Color != "red" || !jobs[0].Broken {
+		t.Fatalf("Finished build should be reported, got %+v", jobs)
+	}
+	details := jobs[0].Details.(TravisJob)
+	expected := []TravisMatrixJob{
+		{ID: 7492304, Number: "124.1", State: "failed", URL: "https://travis-ci.com/camunda/zeebe/jobs/7492304"},
+	}
+	if details.BuildID != 1923477 || details.URL != "https://travis-ci.com/camunda/zeebe/builds/1923477" ||
+		!reflect.DeepEqual(details.FailedJobs, expected) {
+		t.Fatalf("Details should describe the build, got %+v", details)
+	}
+}
+
+func TestTravisInstance_ReceiveWebhook_DiscoveredRepository(t *testing.T) {
+	discovery, _ := NewTravisDiscovery("^zee", "", []string{"release/*"}, 0)
+	instance := &TravisInstance{Name: "camunda", Discovery: discovery, WebhookToken: "secret"}
+
+	jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), travisWebhookBody(t, nil))
+	if err != nil || len(jobs) != 0 {
+		t.Fatalf("Build of unwatched branch should be ignored, got %+v, %v", jobs, err)
+	}
+
+	body := travisWebhookBody(t, strings.NewReplacer(`"branch": "master"`, `"branch": "release/0.22"`))
+	jobs, err = instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), body)
+	if err != nil || len(jobs) != 1 || jobs[0].ID != "zeebe@release/0.22" {
+		t.Fatalf("Build of discovered repository should be reported, got %+v, %v", jobs, err)
+	}
+}
+
+func TestTravisInstance_ReceiveWebhook_IgnoresPullRequestsAndRunningBuilds(t *testing.T) {
+	instance := &TravisInstance{
+		Name:         "camunda",
+		Repos:        []TravisRepository{{Organization: "camunda", Name: "zeebe", Branch: "*"}},
+		WebhookToken: "secret",
+	}
+
+	replacers := []*strings.Replacer{
+		strings.NewReplacer(`"type": "push"`, `"type": "pull_request"`),
+		strings.NewReplacer(`"state": "failed",`, `"state": "started",`),
+	}
+	for _, replacer := range replacers {
+		jobs, err := instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), travisWebhookBody(t, replacer))
+		if err != nil || len(jobs) != 0 {
+			t.Errorf("Build should be ignored, got %+v, %v", jobs, err)
+		}
+	}
+}
+
+func TestTravisInstance_ReceiveWebhook_Rejected(t *testing.T) {
+	instance := &TravisInstance{Name: "camunda", WebhookToken: "secret"}
+
+	_, err := instance.ReceiveWebhook(newWebhookRequest("", ""), travisWebhookBody(t, nil))
+	if statusCodeOf(err) != http.StatusUnauthorized {
+		t.Fatalf("Webhook without token should be rejected, got %v", err)
+	}
+
+	_, err = instance.ReceiveWebhook(newWebhookRequest("?token=secret", ""), []byte("state=failed"))
+	if statusCodeOf(err) != http.StatusBadRequest {
+		t.Fatalf("Webhook without payload should be rejected, got %v", err)
+	}
+}
+
+// travisWebhookBody returns the form encoded body of a Travis webhook for the failed build, modified by the replacer.
+func travisWebhookBody(t *testing.T, replacer *strings.Replacer) []byte {
+	content, err := ioutil.ReadFile("testdata/webhooks/travis_failed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := string(content)
+	if replacer != nil {
+		payload = replacer.Replace(payload)
+	}
+	return []byte(url.Values{"payload": {payload}}.Encode())
+}
@@ -0,0 +1,175 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxWebhookBody is the maximum size of a webhook payload in bytes.
+const maxWebhookBody = 5 << 20
+
+// WebhookReceiver is implemented by providers which can be notified about finished builds, so the dashboard shows
+// them before the next poll.
+type WebhookReceiver interface {
+	// ReceiveWebhook verifies the request along with its body and returns the jobs it reports. The jobs replace the
+	// ones with the same ID, jobs which aren't broken anymore are removed unless the receiver is a webhookKeeper.
+	// Events which don't concern a watched job, e.g. a started build, return no jobs.
+	ReceiveWebhook(r *http.Request, body []byte) ([]Job, error)
+}
+
+// webhookMerger is implemented by WebhookReceivers whose webhooks only report the status of a job. The reported jobs
+// are merged into the stored ones instead of replacing them, so details of the last fetch are kept.
+type webhookMerger interface {
+	mergeWebhookJob(stored Job, reported Job) Job
+}
+
+// webhookKeeper is implemented by WebhookReceivers whose Fetch reports jobs which aren't broken as well, e.g. the blue
+// jobs of the Jenkins Broken view. The reported jobs it keeps stay on the dashboard like the ones of a poll.
+type webhookKeeper interface {
+	keepsWebhookJob(job Job) bool
+}
+
+// WebhookError is returned for webhooks which are rejected, along with the HTTP status to respond with.
+type WebhookError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *WebhookError) Error() string {
+	return e.Message
+}
+
+func webhookErrorf(statusCode int, format string, a ...interface{}) *WebhookError {
+	return &WebhookError{StatusCode: statusCode, Message: fmt.Sprintf(format, a...)}
+}
+
+// ReceiveWebhook passes the webhook request to the instance with the given name and updates its jobs with the reported
+// ones. The updates are only visible while polling, otherwise every request fetches the instances anyway.
+func (d *Dashboard) ReceiveWebhook(instance string, r *http.Request) error {
+	var receiver WebhookReceiver
+	for _, provider := range d.providers {
+		if provider.Info().Name == instance {
+			receiver, _ = provider.(WebhookReceiver)
+			break
+		}
+	}
+	if receiver == nil {
+		return webhookErrorf(http.StatusNotFound, "Instance '%s' doesn't exist or doesn't receive webhooks.", instance)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		return webhookErrorf(http.StatusBadRequest, "Unable to read webhook: %s", err)
+	}
+	if len(body) > maxWebhookBody {
+		return webhookErrorf(http.StatusRequestEntityTooLarge, "Webhook exceeds %d bytes.", maxWebhookBody)
+	}
+
+	jobs, err := receiver.ReceiveWebhook(r, body)
+	if err != nil {
+		return err
+	}
+	d.update(instance, jobs, receiver)
+	return nil
+}
+
+// update replaces the stored jobs of the instance with the given ones, or merges them into the stored ones if the
+// receiver is a webhookMerger. Jobs which aren't broken are removed, as most providers only report their broken jobs,
+// unless the receiver is a webhookKeeper which keeps them. Instances which haven't been fetched yet are left alone,
+// their next fetch includes the update anyway.
+func (d *Dashboard) update(instance string, jobs []Job, receiver WebhookReceiver) {
+	if len(jobs) == 0 {
+		return
+	}
+	merger, _ := receiver.(webhookMerger)
+	keeper, _ := receiver.(webhookKeeper)
+
+	d.stateMutex.Lock()
+	current, ok := d.state[instance]
+	if !ok {
+		d.stateMutex.Unlock()
+		return
+	}
+
+	// the stored aggregation is replaced instead of modified, as it may be copied concurrently
+	updated := current.copy()
+	var removed []Job
+	for _, job := range jobs {
+		index := -1
+		for i := range updated.Jobs {
+			if updated.Jobs[i].ID == job.ID {
+				index = i
+				break
+			}
+		}
+
+		keep := job.Broken || keeper != nil && keeper.keepsWebhookJob(job)
+		switch {
+		case index >= 0 && keep && merger != nil:
+			updated.Jobs[index] = merger.mergeWebhookJob(updated.Jobs[index], job)
+		case index >= 0 && keep:
+			updated.Jobs[index] = job
+		case index >= 0:
+			updated.Jobs = append(updated.Jobs[:index], updated.Jobs[index+1:]...)
+		case keep:
+			updated.Jobs = append(updated.Jobs, job)
+		}
+		if !keep {
+			removed = append(removed, job)
+		}
+	}
+
+	// the transitions are detected including the removed successful jobs, so they are reported as fixed
+	reported := updated.copy()
+	reported.Jobs = append(reported.Jobs, removed...)
+	events := d.transitions(current, reported, time.Now())
+	d.state[instance] = updated
+	d.stateMutex.Unlock()
+
+	d.notify(events)
+}
+
+// verifyWebhookToken checks that the request carries the given token, either in the 'token' query parameter or the
+// 'X-Webhook-Token' header.
+func verifyWebhookToken(r *http.Request, token string) error {
+	if token == "" {
+		return webhookErrorf(http.StatusForbidden, "Webhooks are disabled, no webhook token is configured.")
+	}
+
+	received := r.Header.Get("X-Webhook-Token")
+	if received == "" {
+		received = r.URL.Query().Get("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(received), []byte(token)) != 1 {
+		return webhookErrorf(http.StatusUnauthorized, "Webhook token is missing or invalid.")
+	}
+	return nil
+}
+
+// verifyWebhookSignature checks the HMAC-SHA256 signature of the body in the form 'sha256=<hex digest>' against the
+// given secret.
+func verifyWebhookSignature(body []byte, signature string, secret string) error {
+	if secret == "" {
+		return webhookErrorf(http.StatusForbidden, "Webhooks are disabled, no webhook secret is configured.")
+	}
+
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return webhookErrorf(http.StatusUnauthorized, "Webhook signature is missing or malformed.")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return webhookErrorf(http.StatusUnauthorized, "Webhook signature is invalid.")
+	}
+	return nil
+}
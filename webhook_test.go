@@ -0,0 +1,173 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDashboard_ReceiveWebhook(t *testing.T) {
+	receiver := &TestWebhookReceiver{TestProvider: TestProvider{name: "Test", jobs: []Job{
+		{ID: "deploy", Color: "red", Broken: true},
+		{ID: "docs", Color: "red", Broken: true},
+	}}}
+	instance := New(receiver)
+	instance.setPolling(true)
+	instance.Fetch()
+
+	receiver.jobs = []Job{
+		{ID: "deploy", Color: "green"},
+		{ID: "docs", Color: "aborted", Broken: true},
+		{ID: "release", Color: "red", Broken: true},
+		{ID: "website", Color: "green"},
+	}
+	if err := instance.ReceiveWebhook("Test", newWebhookRequest("", "{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := instance.Fetch()[0].Jobs
+	if len(jobs) != 2 || jobs[0].ID != "docs" || jobs[0].Color != "aborted" || jobs[1].ID != "release" {
+		t.Fatalf("Jobs should be updated by the webhook, got %+v", jobs)
+	}
+	if receiver.body != "{}" {
+		t.Fatalf("Body should be passed to the receiver, got '%s'", receiver.body)
+	}
+}
+
+func TestDashboard_ReceiveWebhook_KeepsSuccessfulJobs(t *testing.T) {
+	receiver := &TestKeepingWebhookReceiver{TestWebhookReceiver{TestProvider: TestProvider{name: "Test", jobs: []Job{
+		{ID: "deploy", Color: "red", Broken: true},
+		{ID: "docs", Color: "blue"},
+	}}}}
+	instance := New(receiver)
+	instance.setPolling(true)
+	instance.Fetch()
+
+	receiver.jobs = []Job{{ID: "deploy", Color: "blue"}, {ID: "website", Color: "blue"}}
+	if err := instance.ReceiveWebhook("Test", newWebhookRequest("", "{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := instance.Fetch()[0].Jobs
+	if len(jobs) != 3 || jobs[0].ID != "deploy" || jobs[0].Broken || jobs[2].ID != "website" {
+		t.Fatalf("Successful jobs should be kept like the ones of a poll, got %+v", jobs)
+	}
+}
+
+func TestDashboard_ReceiveWebhook_BeforeFetch(t *testing.T) {
+	receiver := &TestWebhookReceiver{TestProvider: TestProvider{name: "Test", jobs: []Job{{ID: "deploy", Color: "red", Broken: true}}}}
+	instance := New(receiver)
+
+	if err := instance.ReceiveWebhook("Test", newWebhookRequest("", "{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := instance.state["Test"]; ok {
+		t.Fatal("Instances which haven't been fetched yet shouldn't be updated")
+	}
+}
+
+func TestDashboard_ReceiveWebhook_UnknownInstance(t *testing.T) {
+	instance := New(&TestProvider{name: "Test"})
+
+	for _, name := range []string{"Test", "Missing"} {
+		err := instance.ReceiveWebhook(name, newWebhookRequest("", "{}"))
+		if webhookErr, ok := err.(*WebhookError); !ok || webhookErr.StatusCode != http.StatusNotFound {
+			t.Errorf("Webhook for '%s' should be rejected as not found, got %v", name, err)
+		}
+	}
+}
+
+func TestVerifyWebhookToken(t *testing.T) {
+	header := newWebhookRequest("", "")
+	header.Header.Set("X-Webhook-Token", "secret")
+
+	tests := []struct {
+		request    *http.Request
+		token      string
+		statusCode int
+	}{
+		{newWebhookRequest("?token=secret", ""), "secret", 0},
+		{header, "secret", 0},
+		{newWebhookRequest("?token=guess", ""), "secret", http.StatusUnauthorized},
+		{newWebhookRequest("", ""), "secret", http.StatusUnauthorized},
+		{newWebhookRequest("?token=secret", ""), "", http.StatusForbidden},
+	}
+	for i, test := range tests {
+		err := verifyWebhookToken(test.request, test.token)
+		if statusCode := statusCodeOf(err); statusCode != test.statusCode {
+			t.Errorf("Wrong status of token verification #%d. Expected: %d, got: %v", i, test.statusCode, err)
+		}
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"action": "completed"}`)
+	signature := signWebhook(body, "secret")
+
+	tests := []struct {
+		signature  string
+		secret     string
+		statusCode int
+	}{
+		{signature, "secret", 0},
+		{signature, "other", http.StatusUnauthorized},
+		{strings.TrimPrefix(signature, "sha256="), "secret", http.StatusUnauthorized},
+		{"sha256=zz", "secret", http.StatusUnauthorized},
+		{"", "secret", http.StatusUnauthorized},
+		{signature, "", http.StatusForbidden},
+	}
+	for i, test := range tests {
+		err := verifyWebhookSignature(body, test.signature, test.secret)
+		if statusCode := statusCodeOf(err); statusCode != test.statusCode {
+			t.Errorf("Wrong status of signature verification #%d. Expected: %d, got: %v", i, test.statusCode, err)
+		}
+	}
+}
+
+func newWebhookRequest(query string, body string) *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/dashboard/webhooks/Test"+query, strings.NewReader(body))
+}
+
+func signWebhook(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// statusCodeOf returns the status of a WebhookError, or zero if there is no error.
+func statusCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	if webhookErr, ok := err.(*WebhookError); ok {
+		return webhookErr.StatusCode
+	}
+	return -1
+}
+
+/**
+ * Test implementation of WebhookReceiver
+ */
+
+type TestWebhookReceiver struct {
+	TestProvider
+	body string
+}
+
+func (r *TestWebhookReceiver) ReceiveWebhook(request *http.Request, body []byte) ([]Job, error) {
+	r.body = string(body)
+	return r.jobs, nil
+}
+
+type TestKeepingWebhookReceiver struct {
+	TestWebhookReceiver
+}
+
+func (r *TestKeepingWebhookReceiver) keepsWebhookJob(job Job) bool {
+	return true
+}